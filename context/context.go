@@ -20,11 +20,14 @@ package context
 
 import (
 	"context"
+	"log/slog"
 	"os"
 	"strconv"
 	"strings"
 
 	"google.golang.org/grpc/metadata"
+
+	"github.com/dell/gocsi/utils/slogrus"
 )
 
 // RequestIDKey is the key used to put/get a CSI request ID
@@ -51,16 +54,41 @@ var (
 	// with the signature func(string, string) that can be used to set the
 	// value of an environment variable
 	ctxOSSetenvKey = interface{}("os.Setenev")
+
+	// ctxLoggerKey is an interface-wrapped key used to access the
+	// *slog.Logger used by GoCSI and its middleware.
+	ctxLoggerKey = interface{}("csi.logger")
 )
 
+// defaultLogger is returned by GetLogger when a context has no logger.
+// It writes to the standard logrus logger.
+var defaultLogger = slogrus.New(nil)
+
 type (
 	lookupEnvFunc func(string) (string, bool)
 	setenvFunc    func(string, string) error
 )
 
 // GetRequestID inspects the context for gRPC metadata and returns
-// its request ID if available.
+// its request ID if available and if the ID is an unsigned integer.
+// The IDs generated by the request ID injector are unsigned integers
+// unless it is configured to generate UUIDs. Request IDs sent by clients
+// may be opaque strings, such as UUIDs, in which case
+// GetRequestIDString should be used instead.
 func GetRequestID(ctx context.Context) (uint64, bool) {
+	if szID, ok := GetRequestIDString(ctx); ok {
+		if id, err := strconv.ParseUint(szID, 10, 64); err == nil {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// GetRequestIDString inspects the context for gRPC metadata and returns
+// its request ID if available. The ID is returned as-is, so it may be a
+// number generated by an older version of GoCSI, a UUID, or a correlation
+// ID provided by a container orchestrator.
+func GetRequestIDString(ctx context.Context) (string, bool) {
 	var (
 		szID   []string
 		szIDOK bool
@@ -73,13 +101,41 @@ func GetRequestID(ctx context.Context) (uint64, bool) {
 		szID, szIDOK = md[RequestIDKey]
 	}
 
-	if szIDOK && len(szID) == 1 {
-		if id, err := strconv.ParseUint(szID[0], 10, 64); err == nil {
-			return id, true
-		}
+	if szIDOK && len(szID) == 1 && szID[0] != "" {
+		return szID[0], true
 	}
 
-	return 0, false
+	return "", false
+}
+
+// WithLogger returns a new Context with the provided logger. The logger
+// is used by GoCSI and its middleware for all messages logged on behalf
+// of the context, and may be retrieved by a storage plug-in's handlers
+// with GetLogger.
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxLoggerKey, l)
+}
+
+// GetLogger returns the logger stored in the context by WithLogger. If
+// the context is nil or does not have a logger then a logger that writes
+// to the standard logrus logger is returned.
+func GetLogger(ctx context.Context) *slog.Logger {
+	if ctx == nil {
+		return defaultLogger
+	}
+	if l, ok := ctx.Value(ctxLoggerKey).(*slog.Logger); ok && l != nil {
+		return l
+	}
+	return defaultLogger
+}
+
+// WithLogFields returns a new Context with a logger that includes the
+// provided key/value pairs in every message it logs. The arguments are
+// handled the same way as those of slog.Logger.With, ex.
+//
+//	ctx = WithLogFields(ctx, "volumeID", req.VolumeId)
+func WithLogFields(ctx context.Context, args ...any) context.Context {
+	return WithLogger(ctx, GetLogger(ctx).With(args...))
 }
 
 // WithEnviron returns a new Context with the provided environment variable
@@ -19,7 +19,9 @@
 package context
 
 import (
+	"bytes"
 	"context"
+	"log/slog"
 	"os"
 	"testing"
 
@@ -67,6 +69,58 @@ func TestGetRequestID(t *testing.T) {
 	}
 }
 
+func TestGetRequestIDString(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		wantID        string
+		wantAvailable bool
+	}{
+		{
+			name:          "Negative test: no ID in context",
+			ctx:           context.Background(),
+			wantID:        "",
+			wantAvailable: false,
+		},
+		{
+			name: "Negative test: empty ID",
+			ctx: metadata.NewIncomingContext(context.Background(), metadata.MD{
+				RequestIDKey: []string{""},
+			}),
+			wantID:        "",
+			wantAvailable: false,
+		},
+		{
+			name: "Get UUID request ID from incoming context",
+			ctx: metadata.NewIncomingContext(context.Background(), metadata.MD{
+				RequestIDKey: []string{"9b2b6e0e-3a8c-4b53-9a43-5d1f0c6a7e21"},
+			}),
+			wantID:        "9b2b6e0e-3a8c-4b53-9a43-5d1f0c6a7e21",
+			wantAvailable: true,
+		},
+		{
+			name: "Get numeric request ID from outgoing context",
+			ctx: metadata.NewOutgoingContext(context.Background(), metadata.MD{
+				RequestIDKey: []string{"102"},
+			}),
+			wantID:        "102",
+			wantAvailable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualID, actualAvailable := GetRequestIDString(tt.ctx)
+			assert.Equal(t, tt.wantID, actualID)
+			assert.Equal(t, tt.wantAvailable, actualAvailable)
+
+			// A non-numeric ID is not returned by GetRequestID.
+			_, ok := GetRequestID(tt.ctx)
+			assert.Equal(t, tt.wantID == "102", ok)
+		})
+	}
+}
+
 func TestWithEnviron(t *testing.T) {
 	want := []string{"key=value"}
 
@@ -145,3 +199,20 @@ func TestSetenv(t *testing.T) {
 
 	assert.Equal(t, "value", os.Getenv("key"))
 }
+
+func TestGetLogger(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, defaultLogger, GetLogger(ctx))
+	var nilCtx context.Context
+	assert.Equal(t, defaultLogger, GetLogger(nilCtx))
+
+	buf := &bytes.Buffer{}
+	l := slog.New(slog.NewTextHandler(buf, nil))
+	ctx = WithLogger(ctx, l)
+	assert.Equal(t, l, GetLogger(ctx))
+
+	ctx = WithLogFields(ctx, "requestID", "0001")
+	ctx = WithLogFields(ctx, "volumeID", "vol-1")
+	GetLogger(ctx).Info("hello")
+	assert.Contains(t, buf.String(), "msg=hello requestID=0001 volumeID=vol-1")
+}
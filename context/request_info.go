@@ -0,0 +1,104 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package context
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// ctxRequestInfoKey is an interface-wrapped key used to access the
+// RequestInfo injected into a request's context by GoCSI.
+var ctxRequestInfoKey = interface{}("csi.requestinfo")
+
+// LockType is the type of a lock held on behalf of a request.
+type LockType string
+
+const (
+	// LockTypeVolumeID is the type of a lock on a volume's ID.
+	LockTypeVolumeID LockType = "volumeID"
+
+	// LockTypeVolumeName is the type of a lock on a volume's name.
+	LockTypeVolumeName LockType = "volumeName"
+)
+
+// Lock is a lock held on behalf of a request.
+type Lock struct {
+	// Type is the type of the lock.
+	Type LockType
+
+	// Name is the name of the locked resource, ex. a volume ID.
+	Name string
+}
+
+// RequestInfo is information about a request that is populated by the
+// GoCSI interceptors.
+type RequestInfo struct {
+	// ID is the request's ID. Please see GetRequestIDString.
+	ID string
+
+	// FullMethod is the full gRPC method, ex.
+	// "/csi.v1.Node/NodePublishVolume".
+	FullMethod string
+
+	// Service is the name of the CSI service, ex. "Node".
+	Service string
+
+	// Method is the name of the CSI method, ex. "NodePublishVolume".
+	Method string
+
+	// StartTime is the time at which GoCSI received the request.
+	StartTime time.Time
+
+	// VolumeID is the ID of the volume the request operates on, if any.
+	VolumeID string
+
+	// Locks are the locks held on behalf of the request, ex. by the
+	// serial volume access interceptor.
+	Locks []Lock
+}
+
+// WithRequestInfo returns a new Context with the provided request info.
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	info.Locks = slices.Clone(info.Locks)
+	return context.WithValue(ctx, ctxRequestInfoKey, info)
+}
+
+// GetRequestInfo returns the request info stored in the context by
+// WithRequestInfo. The info's ID is always the one returned by
+// GetRequestIDString, so it reflects an ID injected by an interceptor
+// after the request info was stored.
+func GetRequestInfo(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(ctxRequestInfoKey).(RequestInfo)
+	if id, idOK := GetRequestIDString(ctx); idOK {
+		info.ID = id
+		ok = true
+	}
+	info.Locks = slices.Clone(info.Locks)
+	return info, ok
+}
+
+// WithHeldLock returns a new Context with request info that includes
+// the provided lock in its list of held locks.
+func WithHeldLock(ctx context.Context, lock Lock) context.Context {
+	info, _ := ctx.Value(ctxRequestInfoKey).(RequestInfo)
+	info.Locks = append(slices.Clone(info.Locks), lock)
+	return context.WithValue(ctx, ctxRequestInfoKey, info)
+}
@@ -0,0 +1,66 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package context
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestGetRequestInfo(t *testing.T) {
+	_, ok := GetRequestInfo(context.Background())
+	assert.False(t, ok)
+
+	now := time.Now()
+	ctx := WithRequestInfo(context.Background(), RequestInfo{
+		FullMethod: "/csi.v1.Node/NodePublishVolume",
+		Service:    "Node",
+		Method:     "NodePublishVolume",
+		StartTime:  now,
+		VolumeID:   "vol-1",
+	})
+
+	// The request ID is read from the gRPC metadata.
+	ctx = metadata.NewIncomingContext(ctx,
+		metadata.Pairs(RequestIDKey, "req-1"))
+
+	lock := Lock{Type: LockTypeVolumeID, Name: "vol-1"}
+	lockCtx := WithHeldLock(ctx, lock)
+
+	info, ok := GetRequestInfo(lockCtx)
+	assert.True(t, ok)
+	assert.Equal(t, RequestInfo{
+		ID:         "req-1",
+		FullMethod: "/csi.v1.Node/NodePublishVolume",
+		Service:    "Node",
+		Method:     "NodePublishVolume",
+		StartTime:  now,
+		VolumeID:   "vol-1",
+		Locks:      []Lock{lock},
+	}, info)
+
+	// The parent context does not see the lock.
+	info, ok = GetRequestInfo(ctx)
+	assert.True(t, ok)
+	assert.Empty(t, info.Locks)
+}
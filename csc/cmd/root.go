@@ -156,7 +156,9 @@ var RootCmd = &cobra.Command{
 					if err != nil {
 						return nil, err
 					}
-					return net.DialTimeout(proto, addr, root.timeout)
+					ctx, cancel := context.WithTimeout(root.ctx, root.timeout)
+					defer cancel()
+					return utils.Dial(ctx, proto, addr)
 				}),
 		}
 
@@ -23,8 +23,6 @@ import (
 	"strconv"
 	"strings"
 
-	log "github.com/sirupsen/logrus"
-
 	csictx "github.com/dell/gocsi/context"
 	utils "github.com/dell/gocsi/utils/csi"
 )
@@ -118,10 +116,61 @@ const (
 	// of the VolumeContext field
 	EnvVarLoggingDisableVolCtx = "X_CSI_LOG_DISABLE_VOL_CTX"
 
+	// EnvVarLoggingFormat is the name of the environment variable used
+	// to specify the format of request and response log records. Valid
+	// values are "text" and "json". The default value is "text".
+	//
+	// Setting this environment variable to "json" causes each request and
+	// response to be logged as a single JSON object.
+	EnvVarLoggingFormat = "X_CSI_LOG_FORMAT"
+
+	// EnvVarLoggingRedactFields is the name of the environment variable
+	// used to specify a comma-separated list of field paths that are
+	// redacted from JSON request and response log records, ex.
+	// "parameters.password, publish_context.*token*". Fields named
+	// "secrets" are always redacted.
+	EnvVarLoggingRedactFields = "X_CSI_LOG_REDACT_FIELDS"
+
+	// EnvVarLoggingSlowCallThreshold is the name of the environment
+	// variable used to specify a time.Duration string. Responses to RPCs
+	// that take longer than this duration are logged at the WARN level
+	// instead of the INFO level. This setting has no effect unless
+	// response logging is enabled.
+	EnvVarLoggingSlowCallThreshold = "X_CSI_LOG_SLOW_CALL_THRESHOLD"
+
+	// EnvVarLoggingIncludeMethods is the name of the environment variable
+	// used to specify a comma-separated list of the only methods for which
+	// requests and responses are logged. A method may be specified by its
+	// full gRPC name, by SERVICE/METHOD, or by its name alone, ex.
+	// "CreateVolume, Node/NodePublishVolume".
+	EnvVarLoggingIncludeMethods = "X_CSI_LOG_INCLUDE_METHODS"
+
+	// EnvVarLoggingExcludeMethods is the name of the environment variable
+	// used to specify a comma-separated list of methods for which requests
+	// and responses are not logged, ex. "Probe, NodeGetCapabilities".
+	// Exclusion takes precedence over inclusion.
+	EnvVarLoggingExcludeMethods = "X_CSI_LOG_EXCLUDE_METHODS"
+
+	// EnvVarLoggingSampleRates is the name of the environment variable
+	// used to specify the fraction of calls to a method that are logged in
+	// the format:
+	//
+	//         METHOD=RATE[, METHOD=RATE...]
+	//
+	// RATE is a number from 0.0 (none) to 1.0 (all), and a METHOD of "*"
+	// sets the rate for all other methods. Calls that fail are always
+	// logged regardless of their sampling rate.
+	EnvVarLoggingSampleRates = "X_CSI_LOG_SAMPLE_RATES"
+
 	// EnvVarReqIDInjection is the name of the environment variable
 	// used to determine whether or not to enable request ID injection.
 	EnvVarReqIDInjection = "X_CSI_REQ_ID_INJECTION"
 
+	// EnvVarReqIDUUID is the name of the environment variable used to
+	// determine whether the request IDs generated by the request ID
+	// injector are UUIDs instead of numbers.
+	EnvVarReqIDUUID = "X_CSI_REQ_ID_UUID"
+
 	// EnvVarSpecValidation is the name of the environment variable
 	// used to determine whether or not to enable validation of CSI
 	// request and response messages. Setting X_CSI_SPEC_VALIDATION=true
@@ -140,11 +189,39 @@ const (
 	// a code of "Internal."
 	EnvVarSpecRepValidation = "X_CSI_SPEC_REP_VALIDATION"
 
+	// EnvVarSpecReqValidationWarnOnly is the name of the environment
+	// variable used to determine whether or not invalid CSI request
+	// messages are only logged instead of rejected. Setting this
+	// environment variable to a truthy value enables request validation
+	// unless X_CSI_SPEC_REQ_VALIDATION is explicitly set to false.
+	EnvVarSpecReqValidationWarnOnly = "X_CSI_SPEC_REQ_VALIDATION_WARN_ONLY"
+
+	// EnvVarSpecRepValidationWarnOnly is the name of the environment
+	// variable used to determine whether or not invalid CSI response
+	// messages are only logged instead of marshalled into a gRPC error.
+	// Setting this environment variable to a truthy value enables response
+	// validation unless X_CSI_SPEC_REP_VALIDATION is explicitly set to
+	// false.
+	EnvVarSpecRepValidationWarnOnly = "X_CSI_SPEC_REP_VALIDATION_WARN_ONLY"
+
 	// EnvVarDisableFieldLen is the name of the environment variable used
 	// to determine whether or not to disable validation of CSI request and
 	// response field lengths against the permitted lenghts defined in the spec
 	EnvVarDisableFieldLen = "X_CSI_SPEC_DISABLE_LEN_CHECK"
 
+	// EnvVarSpecFieldLimits is the name of the environment variable used
+	// to override the permitted lengths of specific CSI request and
+	// response fields. The value is a comma-separated list of key/value
+	// pairs in the format:
+	//
+	//         FIELD=MAX[, FIELD=MAX...]
+	//
+	// FIELD is the Go name of a message field, ex. VolumeId. For map fields
+	// the limit applies to the combined size of the map, and appending "[]"
+	// to the field name, ex. VolumeContext[], sets the limit for each of the
+	// map's keys and values.
+	EnvVarSpecFieldLimits = "X_CSI_SPEC_FIELD_LIMITS"
+
 	// EnvVarRequireStagingTargetPath is the name of the environment variable
 	// used to determine whether or not the NodePublishVolume request field
 	// StagingTargetPath is required.
@@ -286,6 +363,126 @@ const (
 	// variable that defines whether or not the TLS connection should
 	// verify certificates.
 	EnvVarSerialVolAccessEtcdTLSInsecure = "X_CSI_SERIAL_VOL_ACCESS_ETCD_TLS_INSECURE"
+
+	// EnvVarIdempotency is the name of the environment variable used to
+	// determine whether or not to enable the caching of the responses of
+	// CreateVolume, CreateSnapshot, and ControllerPublishVolume so that
+	// retries of identical requests receive the original response.
+	EnvVarIdempotency = "X_CSI_IDEMPOTENCY"
+
+	// EnvVarIdempotencyTTL is the name of the environment variable used
+	// to specify the amount of time for which a response is cached.
+	EnvVarIdempotencyTTL = "X_CSI_IDEMPOTENCY_TTL"
+
+	// EnvVarIdempotencyStore is the name of the environment variable used
+	// to specify where responses are cached. Valid values are "memory"
+	// and "etcd". The etcd store connects to the etcd cluster defined by
+	// the X_CSI_SERIAL_VOL_ACCESS_ETCD_* environment variables.
+	EnvVarIdempotencyStore = "X_CSI_IDEMPOTENCY_STORE"
+
+	// EnvVarIdempotencyEtcdDomain is the name of the environment variable
+	// that defines the etcd key prefix under which responses are cached.
+	EnvVarIdempotencyEtcdDomain = "X_CSI_IDEMPOTENCY_ETCD_DOMAIN"
+
+	// EnvVarRateLimitMaxInFlight is the name of the environment variable
+	// used to limit the number of concurrent calls to CSI methods. The
+	// limits are specified using the following comma-separated format:
+	//
+	//     METHOD=MAX[, METHOD=MAX...]
+	//
+	// A METHOD of "*" sets the limit for all other methods.
+	EnvVarRateLimitMaxInFlight = "X_CSI_RATE_LIMIT_MAX_IN_FLIGHT"
+
+	// EnvVarRateLimitRates is the name of the environment variable used to
+	// limit the rate of calls to CSI methods. The limits are specified
+	// using the following comma-separated format:
+	//
+	//     METHOD=RATE[:BURST][, METHOD=RATE[:BURST]...]
+	//
+	// RATE is the number of calls per second and BURST is the number of
+	// calls that may be made at once. A METHOD of "*" sets the limit for
+	// all other methods.
+	EnvVarRateLimitRates = "X_CSI_RATE_LIMIT_RATES"
+
+	// EnvVarRateLimitWait is the name of the environment variable used to
+	// specify how long a call that exceeds a limit waits for the limit to
+	// be satisfied. The value is a duration or "deadline" to wait until
+	// the call's deadline. By default calls are rejected immediately.
+	EnvVarRateLimitWait = "X_CSI_RATE_LIMIT_WAIT"
+
+	// EnvVarRateLimitCode is the name of the environment variable used to
+	// specify the gRPC code returned for calls that exceed a limit, ex.
+	// "Unavailable". The default value is "ResourceExhausted".
+	EnvVarRateLimitCode = "X_CSI_RATE_LIMIT_CODE"
+
+	// EnvVarRateLimitKeyParam is the name of the environment variable used
+	// to specify a request parameter, ex. the name of a storage array, whose
+	// values are limited separately.
+	EnvVarRateLimitKeyParam = "X_CSI_RATE_LIMIT_KEY_PARAM"
+
+	// EnvVarDeadlineDefaults is the name of the environment variable used
+	// to specify the timeouts applied to calls to CSI methods that do not
+	// have a deadline. The timeouts are specified using the following
+	// comma-separated format:
+	//
+	//     METHOD=DURATION[, METHOD=DURATION...]
+	//
+	// A METHOD of "*" sets the timeout for all other methods.
+	EnvVarDeadlineDefaults = "X_CSI_DEADLINE_DEFAULTS"
+
+	// EnvVarDeadlineMax is the name of the environment variable used to
+	// specify the maximum timeouts of calls to CSI methods. The format is
+	// the same as for X_CSI_DEADLINE_DEFAULTS.
+	EnvVarDeadlineMax = "X_CSI_DEADLINE_MAX"
+
+	// EnvVarDeadlineOrphanWarning is the name of the environment variable
+	// used to specify how long after a call's deadline a warning is logged
+	// if the call's handler is still running.
+	EnvVarDeadlineOrphanWarning = "X_CSI_DEADLINE_ORPHAN_WARNING"
+
+	// EnvVarDisablePanicRecovery is the name of the environment variable
+	// used to disable the recovery from panics in CSI handlers. When
+	// disabled, a panic crashes the process.
+	EnvVarDisablePanicRecovery = "X_CSI_DISABLE_PANIC_RECOVERY"
+
+	// EnvVarAuthzPolicy is the name of the environment variable used to
+	// specify the path to a JSON policy file that maps caller identities
+	// to the CSI methods they may call. Setting this environment variable
+	// enables authorization. Callers are identified by the verified
+	// certificates of mTLS connections or, for UNIX sockets, by the user
+	// and group IDs of the peer process. The IDs of the peer process are
+	// not available if the SP's ServerOpts set the server's credentials.
+	EnvVarAuthzPolicy = "X_CSI_AUTHZ_POLICY"
+
+	// EnvVarSecretsResolution is the name of the environment variable
+	// used to enable the resolution of references in the Secrets field of
+	// CSI requests, ex. "file:///etc/creds/password" or "env:PASSWORD".
+	EnvVarSecretsResolution = "X_CSI_SECRETS_RESOLUTION"
+
+	// EnvVarSecretsFileDirs is the name of the environment variable used
+	// to specify a comma-separated list of directories. Only files in
+	// these directories may be referenced by secrets. If not set, no file
+	// may be referenced.
+	EnvVarSecretsFileDirs = "X_CSI_SECRETS_FILE_DIRS"
+
+	// EnvVarSecretsEnvVars is the name of the environment variable used
+	// to specify a comma-separated list of the environment variables that
+	// may be referenced by secrets. A name that ends with "*" permits the
+	// environment variables with the preceding prefix. If not set, no
+	// environment variable may be referenced.
+	EnvVarSecretsEnvVars = "X_CSI_SECRETS_ENV_VARS"
+
+	// EnvVarFaults is the name of the environment variable used to
+	// specify a JSON encoded list of faults to inject into calls to CSI
+	// methods. Setting this environment variable enables fault injection.
+	// Please see the faultinject package for the format of the faults.
+	EnvVarFaults = "X_CSI_FAULTS"
+
+	// EnvVarFaultInjectionRPC is the name of the environment variable
+	// used to enable the FaultInjector debug service that allows clients
+	// to change the injected faults at runtime. Enabling the debug service
+	// enables fault injection.
+	EnvVarFaultInjectionRPC = "X_CSI_FAULT_INJECTION_RPC"
 )
 
 func (sp *StoragePlugin) initEnvVars(ctx context.Context) {
@@ -337,21 +534,21 @@ func (sp *StoragePlugin) initPluginInfo(ctx context.Context) {
 		return
 	}
 	info := strings.SplitN(szInfo, ",", 3)
-	fields := map[string]interface{}{}
+	var fields []any
 	if len(info) > 0 {
 		sp.pluginInfo.Name = strings.TrimSpace(info[0])
-		fields["name"] = sp.pluginInfo.Name
+		fields = append(fields, "name", sp.pluginInfo.Name)
 	}
 	if len(info) > 1 {
 		sp.pluginInfo.VendorVersion = strings.TrimSpace(info[1])
-		fields["vendorVersion"] = sp.pluginInfo.VendorVersion
+		fields = append(fields, "vendorVersion", sp.pluginInfo.VendorVersion)
 	}
 	if len(info) > 2 {
 		sp.pluginInfo.Manifest = utils.ParseMap(strings.TrimSpace(info[2]))
-		fields["manifest"] = sp.pluginInfo.Manifest
+		fields = append(fields, "manifest", sp.pluginInfo.Manifest)
 	}
 
 	if len(fields) > 0 {
-		log.WithFields(fields).Debug("init plug-in info")
+		csictx.GetLogger(ctx).Debug("init plug-in info", fields...)
 	}
 }
@@ -29,6 +29,7 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"log/slog"
 	"net"
 	"os"
 	"os/signal"
@@ -45,6 +46,8 @@ import (
 	"google.golang.org/grpc"
 
 	csictx "github.com/dell/gocsi/context"
+	"github.com/dell/gocsi/middleware/faultinject"
+	"github.com/dell/gocsi/middleware/secrets"
 	utils "github.com/dell/gocsi/utils/csi"
 	"github.com/dell/gocsi/utils/middleware"
 )
@@ -59,6 +62,12 @@ func Run(
 	appName, appDescription, appUsage string,
 	sp StoragePluginProvider,
 ) {
+	// Prefer the SP's logger, if any, over the one in the context.
+	if p, ok := sp.(*StoragePlugin); ok && p.Logger != nil {
+		ctx = csictx.WithLogger(ctx, p.Logger)
+	}
+	lg := csictx.GetLogger(ctx)
+
 	// Check for the debug value.
 	if v, ok := csictx.LookupEnv(ctx, EnvVarDebug); ok {
 		/* #nosec G104 */
@@ -76,6 +85,13 @@ func Run(
 		if lvl, err = log.ParseLevel(v); err != nil {
 			lvl = log.InfoLevel
 		}
+
+		// The SP's logger is limited to the log level as well.
+		if p, ok := sp.(*StoragePlugin); ok && p.Logger != nil {
+			p.Logger = slog.New(newLevelHandler(p.Logger.Handler(), lvl))
+			ctx = csictx.WithLogger(ctx, p.Logger)
+			lg = p.Logger
+		}
 	}
 	log.SetLevel(lvl)
 
@@ -95,10 +111,13 @@ func Run(
 
 		t, err := template.New("t").Parse(usage)
 		if err != nil {
-			log.WithError(err).Fatalln("failed to parse usage template")
+			lg.Error("failed to parse usage template", "error", err)
+			osExit(1)
+			return
 		}
 		if err := t.Execute(os.Stderr, app); err != nil {
-			log.WithError(err).Fatalln("failed emitting usage")
+			lg.Error("failed emitting usage", "error", err)
+			osExit(1)
 		}
 		return
 	}
@@ -114,44 +133,52 @@ func Run(
 		osExit(1)
 	}
 
-	// If no endpoint is set then print the usage.
-	if os.Getenv(EnvVarEndpoint) == "" {
+	// If no endpoint is set and no sockets were passed to the process by
+	// systemd socket activation then print the usage.
+	if os.Getenv(EnvVarEndpoint) == "" && !utils.IsSocketActivated() {
 		printUsage()
 		osExit(1)
 	}
 
 	l, err := utils.GetCSIEndpointListener()
 	if err != nil {
-		log.WithError(err).Info("failed to listen")
+		lg.Info("failed to listen", "error", err)
 		osExit(1)
 	}
+	al, activated := l.(*utils.ActivatedListener)
+	if activated {
+		lg.Info("using activated socket",
+			"name", al.Name, "addr", l.Addr().String())
+	}
 
 	// Define a lambda that can be used in the exit handler
-	// to remove a potential UNIX sock file.
+	// to remove a potential UNIX sock file. An activated socket's
+	// file is owned by systemd and must survive restarts.
 	var rmSockFileOnce sync.Once
 	rmSockFile := func() {
 		rmSockFileOnce.Do(func() {
-			if l == nil || l.Addr() == nil {
+			if l == nil || l.Addr() == nil || activated {
 				return
 			}
 			/* #nosec G104 */
-			if l.Addr().Network() == netUnix {
+			if l.Addr().Network() == netUnix &&
+				!utils.IsAbstractSock(l.Addr().String()) {
 				sockFile := l.Addr().String()
 				_ = os.RemoveAll(sockFile)
-				log.WithField("path", sockFile).Info("removed sock file")
+				lg.Info("removed sock file", "path", sockFile)
 			}
 		})
 	}
 
-	trapSignals(func() {
+	trapSignals(lg, func() {
 		sp.GracefulStop(ctx)
 		rmSockFile()
-		log.Info("server stopped gracefully")
+		lg.Info("server stopped gracefully")
 	})
 
 	if err := sp.Serve(ctx, l); err != nil {
 		rmSockFile()
-		log.WithError(err).Info("grpc failed")
+		lg.Info("grpc failed", "error", err)
 		osExit(1)
 	}
 }
@@ -202,8 +229,37 @@ type StoragePlugin struct {
 	// serving the SP. This list should not include the interceptors
 	// defined in the GoCSI package as those are configured by default
 	// based on runtime configuration settings.
+	//
+	// An interceptor may be limited to some of the SP's RPCs with the
+	// combinators in the utils/middleware package, ex.
+	// middleware.ForServices([]string{"Node"}, i) or
+	// middleware.ExceptMethods([]string{"Probe"}, i).
+	//
+	// The interceptors precede the built-in middleware, so the request
+	// context does not yet have the SP's logger or request ID, and calls
+	// reach the interceptors before they are authorized. Please see
+	// Middleware to place an interceptor among the built-in middleware,
+	// ex. after MiddlewareAuthz.
 	Interceptors []grpc.UnaryServerInterceptor
 
+	// Middleware is a list of named interceptors placed before or after
+	// the built-in middleware, ex. an authorization interceptor before
+	// MiddlewareLogging or a metrics interceptor before
+	// MiddlewareSerialVolume to measure the time spent waiting for the
+	// volume's lock. Please see BuiltinMiddleware for the names and the
+	// default order of the built-in middleware.
+	Middleware []Middleware
+
+	// MiddlewareOrder is an optional list of the names of the built-in
+	// middleware and the SP's Middleware that overrides their order.
+	// The list must begin with MiddlewareContext and include every enabled
+	// middleware, including MiddlewareRecovery unless panic recovery is
+	// disabled, or the SP fails to start. A disabled built-in middleware
+	// in the list is ignored. The Before and After fields of the SP's
+	// Middleware are ignored. The SP's Interceptors always precede the
+	// middleware.
+	MiddlewareOrder []string
+
 	// BeforeServe is an optional callback that is invoked after the
 	// StoragePlugin has been initialized, just prior to the creation
 	// of the gRPC server. This callback may be used to perform custom
@@ -219,12 +275,32 @@ type StoragePlugin struct {
 	// for proprietary extensions.
 	RegisterAdditionalServers func(*grpc.Server)
 
+	// Logger is an optional logger used by the SP and the GoCSI
+	// middleware. The logger is also injected into the context of
+	// every request, along with the request's method and volume ID,
+	// and may be retrieved by the SP's handlers with csictx.GetLogger.
+	// If nil, the logger from the context passed to Serve is used,
+	// which by default writes to the standard logrus logger. When the
+	// SP is launched with Run and X_CSI_LOG_LEVEL is set, records below
+	// the log level are discarded by the logger as well.
+	Logger *slog.Logger
+
+	// SecretsResolvers is an optional map of URL schemes to the resolvers
+	// used for references to secrets with the scheme, ex. a client of a
+	// vault for "vault:" references. If not empty, references in the
+	// Secrets field of CSI requests are resolved before they are passed
+	// to the SP, in addition to the "file:" and "env:" references resolved
+	// when X_CSI_SECRETS_RESOLUTION is enabled.
+	SecretsResolvers map[string]secrets.Resolver
+
 	serveOnce sync.Once
 	stopOnce  sync.Once
 	server    *grpc.Server
+	logger    *slog.Logger
 
-	envVars    map[string]string
-	pluginInfo csi.GetPluginInfoResponse
+	envVars       map[string]string
+	faultInjector *faultinject.Injector
+	pluginInfo    csi.GetPluginInfoResponse
 }
 
 // Serve accepts incoming connections on the listener lis, creating
@@ -240,6 +316,11 @@ func (sp *StoragePlugin) Serve(ctx context.Context, lis net.Listener) error {
 		// important and should not be altered unless by someone aware
 		// of how they work.
 
+		// Adding the logger to the context allows `csictx.GetLogger`
+		// to return this SP's logger.
+		sp.logger = sp.getLogger(ctx)
+		ctx = csictx.WithLogger(ctx, sp.logger)
+
 		// Adding this function to the context allows `csictx.LookupEnv`
 		// to search this SP's default env vars for a value.
 		ctx = csictx.WithLookupEnv(ctx, sp.lookupEnv)
@@ -267,6 +348,9 @@ func (sp *StoragePlugin) Serve(ctx context.Context, lis net.Listener) error {
 		// Initialize the interceptors.
 		sp.initInterceptors(ctx)
 
+		// Obtain the identities of UNIX socket peers for authorization.
+		sp.initAuthzCreds(ctx, lis)
+
 		// Invoke the SP's BeforeServe function to give the SP a chance
 		// to perform any local initialization routines.
 		if f := sp.BeforeServe; f != nil {
@@ -299,7 +383,7 @@ func (sp *StoragePlugin) Serve(ctx context.Context, lis net.Listener) error {
 
 		// Always register the identity service.
 		csi.RegisterIdentityServer(sp.server, sp.Identity)
-		log.Info("identity service registered")
+		sp.logger.Info("identity service registered")
 
 		// Determine which of the controller/node services to register
 		mode := csictx.Getenv(ctx, EnvVarMode)
@@ -317,7 +401,7 @@ func (sp *StoragePlugin) Serve(ctx context.Context, lis net.Listener) error {
 				return
 			}
 			csi.RegisterControllerServer(sp.server, sp.Controller)
-			log.Info("controller service registered")
+			sp.logger.Info("controller service registered")
 		}
 		if mode == "" || mode == "node" {
 			if sp.Node == nil {
@@ -325,7 +409,14 @@ func (sp *StoragePlugin) Serve(ctx context.Context, lis net.Listener) error {
 				return
 			}
 			csi.RegisterNodeServer(sp.server, sp.Node)
-			log.Info("node service registered")
+			sp.logger.Info("node service registered")
+		}
+
+		// Register the fault injection debug service if enabled.
+		if sp.faultInjector != nil &&
+			sp.getEnvBool(ctx, EnvVarFaultInjectionRPC) {
+			faultinject.RegisterDebugService(sp.server, sp.faultInjector)
+			sp.logger.Warn("fault injection debug service registered")
 		}
 
 		// Register any additional servers required.
@@ -336,7 +427,7 @@ func (sp *StoragePlugin) Serve(ctx context.Context, lis net.Listener) error {
 		endpoint := fmt.Sprintf(
 			"%s://%s",
 			lis.Addr().Network(), lis.Addr().String())
-		log.WithField("endpoint", endpoint).Info("serving")
+		sp.logger.Info("serving", "endpoint", endpoint)
 
 		// Start the gRPC server.
 		err = sp.server.Serve(lis)
@@ -350,33 +441,46 @@ func (sp *StoragePlugin) Serve(ctx context.Context, lis net.Listener) error {
 // It cancels all active RPCs on the server side and the corresponding
 // pending RPCs on the client side will get notified by connection
 // errors.
-func (sp *StoragePlugin) Stop(_ context.Context) {
+func (sp *StoragePlugin) Stop(ctx context.Context) {
 	sp.stopOnce.Do(func() {
 		if sp.server != nil {
 			sp.server.Stop()
 		}
-		log.Info("stopped")
+		sp.getLogger(ctx).Info("stopped")
 	})
 }
 
 // GracefulStop stops the gRPC server gracefully. It stops the server
 // from accepting new connections and RPCs and blocks until all the
 // pending RPCs are finished.
-func (sp *StoragePlugin) GracefulStop(_ context.Context) {
+func (sp *StoragePlugin) GracefulStop(ctx context.Context) {
 	sp.stopOnce.Do(func() {
 		if sp.server != nil {
 			sp.server.GracefulStop()
 		}
-		log.Info("gracefully stopped")
+		sp.getLogger(ctx).Info("gracefully stopped")
 	})
 }
 
+// getLogger returns the SP's logger. If the SP does not have a logger
+// then the logger from the provided context is returned.
+func (sp *StoragePlugin) getLogger(ctx context.Context) *slog.Logger {
+	if sp.Logger != nil {
+		return sp.Logger
+	}
+	if sp.logger != nil {
+		return sp.logger
+	}
+	return csictx.GetLogger(ctx)
+}
+
 const netUnix = "unix"
 
 func (sp *StoragePlugin) initEndpointPerms(
 	ctx context.Context, lis net.Listener,
 ) error {
-	if lis.Addr().Network() != netUnix {
+	if lis.Addr().Network() != netUnix ||
+		utils.IsAbstractSock(lis.Addr().String()) {
 		return nil
 	}
 
@@ -392,10 +496,7 @@ func (sp *StoragePlugin) initEndpointPerms(
 	p := lis.Addr().String()
 	m := os.FileMode(u)
 
-	log.WithFields(map[string]interface{}{
-		"path": p,
-		"mode": m,
-	}).Info("chmod csi endpoint")
+	csictx.GetLogger(ctx).Info("chmod csi endpoint", "path", p, "mode", m)
 
 	if err := os.Chmod(p, m); err != nil {
 		return err
@@ -407,7 +508,8 @@ func (sp *StoragePlugin) initEndpointPerms(
 func (sp *StoragePlugin) initEndpointOwner(
 	ctx context.Context, lis net.Listener,
 ) error {
-	if lis.Addr().Network() != netUnix {
+	if lis.Addr().Network() != netUnix ||
+		utils.IsAbstractSock(lis.Addr().String()) {
 		return nil
 	}
 
@@ -477,11 +579,10 @@ func (sp *StoragePlugin) initEndpointOwner(
 
 	if uid != puid || gid != pgid {
 		f := lis.Addr().String()
-		log.WithFields(map[string]interface{}{
-			"uid":  usrName,
-			"gid":  grpName,
-			"path": f,
-		}).Info("chown csi endpoint")
+		csictx.GetLogger(ctx).Info("chown csi endpoint",
+			"uid", usrName,
+			"gid", grpName,
+			"path", f)
 		if err := os.Chown(f, uid, gid); err != nil {
 			return err
 		}
@@ -511,7 +612,7 @@ func (sp *StoragePlugin) getEnvBool(ctx context.Context, key string) bool {
 	return false
 }
 
-func trapSignals(onExit func()) {
+func trapSignals(lg *slog.Logger, onExit func()) {
 	sigc := make(chan os.Signal, 1)
 	sigs := []os.Signal{
 		syscall.SIGTERM,
@@ -522,7 +623,7 @@ func trapSignals(onExit func()) {
 	signal.Notify(sigc, sigs...)
 	go func() {
 		for s := range sigc {
-			log.WithField("signal", s).Info("received signal; shutting down")
+			lg.Info("received signal; shutting down", "signal", s)
 			if onExit != nil {
 				onExit()
 			}
@@ -552,3 +653,40 @@ func newLogger(f func(msg string, args ...interface{})) *logger {
 func (l *logger) Write(data []byte) (int, error) {
 	return l.w.Write(data)
 }
+
+// levelHandler is a slog.Handler that discards the records below a
+// minimum level before they reach the wrapped handler.
+type levelHandler struct {
+	slog.Handler
+	level slog.Level
+}
+
+func newLevelHandler(h slog.Handler, lvl log.Level) *levelHandler {
+	if lh, ok := h.(*levelHandler); ok {
+		h = lh.Handler
+	}
+	level := slog.LevelError
+	switch lvl {
+	case log.TraceLevel:
+		level = slog.LevelDebug - 4
+	case log.DebugLevel:
+		level = slog.LevelDebug
+	case log.InfoLevel:
+		level = slog.LevelInfo
+	case log.WarnLevel:
+		level = slog.LevelWarn
+	}
+	return &levelHandler{Handler: h, level: level}
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return lvl >= h.level && h.Handler.Enabled(ctx, lvl)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
@@ -19,11 +19,14 @@
 package gocsi
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"log/slog"
 	"net"
 	"os"
 	"os/user"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -32,10 +35,20 @@ import (
 	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
+	csictx "github.com/dell/gocsi/context"
+	"github.com/dell/gocsi/middleware/faultinject"
+	"github.com/dell/gocsi/middleware/secrets"
 	"github.com/dell/gocsi/mock/service"
+	"github.com/dell/gocsi/utils/middleware"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestRun(t *testing.T) {
@@ -567,3 +580,211 @@ func TestStoragePlugin_Serve(t *testing.T) {
 		})
 	}
 }
+
+func TestInjectContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sp := &StoragePlugin{
+		Logger: slog.New(slog.NewTextHandler(buf, nil)),
+	}
+
+	_, err := sp.injectContext(
+		context.Background(),
+		&csi.NodeUnpublishVolumeRequest{VolumeId: "vol-1"},
+		&grpc.UnaryServerInfo{FullMethod: "/csi.v1.Node/NodeUnpublishVolume"},
+		func(ctx context.Context, _ interface{}) (interface{}, error) {
+			csictx.GetLogger(ctx).Info("handled")
+			info, ok := csictx.GetRequestInfo(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, "Node", info.Service)
+			assert.Equal(t, "NodeUnpublishVolume", info.Method)
+			assert.Equal(t, "vol-1", info.VolumeID)
+			assert.False(t, info.StartTime.IsZero())
+			return nil, nil
+		})
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "msg=handled")
+	assert.Contains(t, buf.String(), "method=/csi.v1.Node/NodeUnpublishVolume")
+	assert.Contains(t, buf.String(), "volumeID=vol-1")
+
+	// The source volume is the volume a snapshot request targets.
+	_, err = sp.injectContext(
+		context.Background(),
+		&csi.CreateSnapshotRequest{SourceVolumeId: "vol-2"},
+		&grpc.UnaryServerInfo{FullMethod: "/csi.v1.Controller/CreateSnapshot"},
+		func(ctx context.Context, _ interface{}) (interface{}, error) {
+			info, _ := csictx.GetRequestInfo(ctx)
+			assert.Equal(t, "vol-2", info.VolumeID)
+			return nil, nil
+		})
+	assert.NoError(t, err)
+}
+
+func TestLevelHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	lg := slog.New(newLevelHandler(slog.NewTextHandler(buf,
+		&slog.HandlerOptions{Level: slog.LevelDebug}), log.WarnLevel))
+	lg = lg.With("k", "v")
+	lg.Info("discarded")
+	lg.Warn("logged")
+	assert.NotContains(t, buf.String(), "discarded")
+	assert.Contains(t, buf.String(), "msg=logged k=v")
+
+	// The level of the wrapped handler still applies.
+	buf.Reset()
+	lg = slog.New(newLevelHandler(slog.NewTextHandler(buf, nil),
+		log.DebugLevel))
+	lg.Debug("discarded")
+	assert.Empty(t, buf.String())
+}
+
+func TestInitInterceptorsSlowCallThreshold(t *testing.T) {
+	t.Setenv(EnvVarRepLogging, "true")
+	t.Setenv(EnvVarLoggingSlowCallThreshold, "500")
+	buf := &bytes.Buffer{}
+	ctx := csictx.WithLogger(context.Background(),
+		slog.New(slog.NewTextHandler(buf, nil)))
+
+	sp := &StoragePlugin{}
+	sp.initInterceptors(ctx)
+	assert.Contains(t, buf.String(), `msg="invalid slow call threshold" value=500`)
+}
+
+func TestInitInterceptorsSecretsResolvers(t *testing.T) {
+	t.Setenv("GOCSI_TEST_SECRET", "from-env")
+	sp := &StoragePlugin{
+		SecretsResolvers: map[string]secrets.Resolver{
+			"vault": secrets.ResolverFunc(
+				func(context.Context, string) (string, error) {
+					return "from-vault", nil
+				}),
+		},
+	}
+	sp.initInterceptors(context.Background())
+
+	var got *csi.CreateVolumeRequest
+	_, err := middleware.ChainUnaryServer(sp.Interceptors...)(
+		context.Background(),
+		&csi.CreateVolumeRequest{
+			Name: "vol",
+			Secrets: map[string]string{
+				"token":    "vault:token",
+				"password": "env:GOCSI_TEST_SECRET",
+			},
+		},
+		&grpc.UnaryServerInfo{FullMethod: "/csi.v1.Controller/CreateVolume"},
+		func(_ context.Context, req interface{}) (interface{}, error) {
+			got = req.(*csi.CreateVolumeRequest)
+			return &csi.CreateVolumeResponse{}, nil
+		})
+	assert.NoError(t, err)
+
+	// Only the SP's resolvers are used unless X_CSI_SECRETS_RESOLUTION
+	// is enabled.
+	assert.Equal(t, map[string]string{
+		"token":    "from-vault",
+		"password": "env:GOCSI_TEST_SECRET",
+	}, got.Secrets)
+}
+
+func TestInitInterceptorsSecretsEnvVars(t *testing.T) {
+	t.Setenv(EnvVarSecretsResolution, "true")
+	t.Setenv(EnvVarSecretsEnvVars, "GOCSI_TEST_SECRET")
+	t.Setenv("GOCSI_TEST_SECRET", "from-env")
+	sp := &StoragePlugin{}
+	sp.initInterceptors(context.Background())
+
+	call := func(ref string) (*csi.CreateVolumeRequest, error) {
+		var got *csi.CreateVolumeRequest
+		_, err := middleware.ChainUnaryServer(sp.Interceptors...)(
+			context.Background(),
+			&csi.CreateVolumeRequest{
+				Name:    "vol",
+				Secrets: map[string]string{"password": ref},
+			},
+			&grpc.UnaryServerInfo{FullMethod: "/csi.v1.Controller/CreateVolume"},
+			func(_ context.Context, req interface{}) (interface{}, error) {
+				got = req.(*csi.CreateVolumeRequest)
+				return &csi.CreateVolumeResponse{}, nil
+			})
+		return got, err
+	}
+
+	got, err := call("env:GOCSI_TEST_SECRET")
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", got.Secrets["password"])
+
+	// Files and the environment variables that are not permitted may not
+	// be referenced.
+	for _, ref := range []string{"env:PATH", "file:///etc/passwd"} {
+		_, err = call(ref)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), ref)
+	}
+}
+
+func TestFaultInjectionRPC(t *testing.T) {
+	svc := service.NewServer()
+	sp := newMockStoragePlugin(svc, svc, svc)
+	sp.EnvVars = append(sp.EnvVars,
+		EnvVarFaultInjectionRPC+"=true",
+		EnvVarFaults+`=[{"method":"Probe","code":"UNAVAILABLE"}]`)
+
+	sock := filepath.Join(t.TempDir(), "csi.sock")
+	lis, err := net.Listen("unix", sock)
+	require.NoError(t, err)
+	ctx := context.Background()
+	go sp.Serve(ctx, lis)
+	defer sp.Stop(ctx)
+
+	conn, err := grpc.NewClient("unix://"+sock,
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := csi.NewIdentityClient(conn)
+
+	_, err = client.Probe(ctx, &csi.ProbeRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	require.NoError(t, conn.Invoke(ctx, faultinject.SetFaultsMethod,
+		wrapperspb.String("[]"), &emptypb.Empty{}))
+	_, err = client.Probe(ctx, &csi.ProbeRequest{})
+	assert.NoError(t, err)
+}
+
+func TestInitAuthzCreds(t *testing.T) {
+	t.Setenv(EnvVarAuthzPolicy, "/policy.json")
+	lis, err := net.Listen("unix", filepath.Join(t.TempDir(), "csi.sock"))
+	require.NoError(t, err)
+	defer lis.Close()
+
+	tests := []struct {
+		name string
+		opts []grpc.ServerOption
+		want int
+	}{
+		{
+			name: "no options",
+			want: 1,
+		},
+		{
+			name: "other options",
+			opts: []grpc.ServerOption{grpc.MaxRecvMsgSize(1024)},
+			want: 2,
+		},
+		{
+			name: "credentials",
+			opts: []grpc.ServerOption{
+				grpc.MaxRecvMsgSize(1024),
+				grpc.Creds(insecure.NewCredentials()),
+			},
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := &StoragePlugin{ServerOpts: tt.opts}
+			sp.initAuthzCreds(context.Background(), lis)
+			assert.Len(t, sp.ServerOpts, tt.want)
+			assert.True(t, hasCreds(sp.ServerOpts))
+		})
+	}
+}
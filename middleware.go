@@ -19,32 +19,53 @@
 package gocsi
 
 import (
+	"fmt"
+	"net"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
-	log "github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 
 	csictx "github.com/dell/gocsi/context"
+	"github.com/dell/gocsi/middleware/authz"
+	"github.com/dell/gocsi/middleware/deadline"
+	"github.com/dell/gocsi/middleware/faultinject"
+	"github.com/dell/gocsi/middleware/idempotency"
+	idemetcd "github.com/dell/gocsi/middleware/idempotency/etcd"
 	"github.com/dell/gocsi/middleware/logging"
+	"github.com/dell/gocsi/middleware/ratelimit"
+	"github.com/dell/gocsi/middleware/recovery"
 	"github.com/dell/gocsi/middleware/requestid"
+	"github.com/dell/gocsi/middleware/secrets"
 	"github.com/dell/gocsi/middleware/serialvolume"
 	"github.com/dell/gocsi/middleware/serialvolume/etcd"
 	"github.com/dell/gocsi/middleware/specvalidator"
+	utils "github.com/dell/gocsi/utils/csi"
 	"github.com/dell/gocsi/utils/rpcs"
 )
 
 func (sp *StoragePlugin) initInterceptors(ctx context.Context) {
-	sp.Interceptors = append(sp.Interceptors, sp.injectContext)
-	log.Debug("enabled context injector")
+	var (
+		lg       = csictx.GetLogger(ctx)
+		builtins = map[string]grpc.UnaryServerInterceptor{}
+	)
+
+	builtins[MiddlewareContext] = sp.injectContext
+	lg.Debug("enabled context injector")
 
 	var (
 		withReqLogging         = sp.getEnvBool(ctx, EnvVarReqLogging)
 		withRepLogging         = sp.getEnvBool(ctx, EnvVarRepLogging)
+		withReqIDInjection     = sp.getEnvBool(ctx, EnvVarReqIDInjection)
 		withDisableLogVolCtx   = sp.getEnvBool(ctx, EnvVarLoggingDisableVolCtx)
 		withSerialVol          = sp.getEnvBool(ctx, EnvVarSerialVolAccess)
 		withSpec               = sp.getEnvBool(ctx, EnvVarSpecValidation)
+		withSpecReqWarnOnly    = sp.getEnvBool(ctx, EnvVarSpecReqValidationWarnOnly)
+		withSpecRepWarnOnly    = sp.getEnvBool(ctx, EnvVarSpecRepValidationWarnOnly)
 		withStgTgtPath         = sp.getEnvBool(ctx, EnvVarRequireStagingTargetPath)
 		withVolContext         = sp.getEnvBool(ctx, EnvVarRequireVolContext)
 		withPubContext         = sp.getEnvBool(ctx, EnvVarRequirePubContext)
@@ -73,7 +94,7 @@ func (sp *StoragePlugin) initInterceptors(ctx context.Context) {
 		withSpecReq = withSpec
 		withSpecRep = withSpec
 	)
-	log.WithField("withSpec", withSpec).Debug("init req & rep validation")
+	lg.Debug("init req & rep validation", "withSpec", withSpec)
 
 	// If request validation is not enabled explicitly, check to see if it
 	// should be enabled implicitly.
@@ -81,49 +102,129 @@ func (sp *StoragePlugin) initInterceptors(ctx context.Context) {
 		withSpecReq = withCreds ||
 			withStgTgtPath ||
 			withVolContext ||
-			withPubContext
-		log.WithField("withSpecReq", withSpecReq).Debug(
-			"init implicit req validation")
+			withPubContext ||
+			withSpecReqWarnOnly
+		lg.Debug("init implicit req validation", "withSpecReq", withSpecReq)
+	}
+	if !withSpecRep {
+		withSpecRep = withSpecRepWarnOnly
+		lg.Debug("init implicit rep validation", "withSpecRep", withSpecRep)
 	}
 
 	// Check to see if spec request or response validation are overridden.
 	if v, ok := csictx.LookupEnv(ctx, EnvVarSpecReqValidation); ok {
 		withSpecReq, _ = strconv.ParseBool(v)
-		log.WithField("withSpecReq", withSpecReq).Debug("init req validation")
+		lg.Debug("init req validation", "withSpecReq", withSpecReq)
 	}
 	if v, ok := csictx.LookupEnv(ctx, EnvVarSpecRepValidation); ok {
 		withSpecRep, _ = strconv.ParseBool(v)
-		log.WithField("withSpecRep", withSpecRep).Debug("init rep validation")
+		lg.Debug("init rep validation", "withSpecRep", withSpecRep)
+	}
+
+	// Configure request ID injection. Request ID injection is enabled
+	// automatically if logging is enabled.
+	if withReqIDInjection || withReqLogging || withRepLogging {
+		var reqIDOpts []requestid.Option
+		if sp.getEnvBool(ctx, EnvVarReqIDUUID) {
+			reqIDOpts = append(reqIDOpts, requestid.WithUUIDs())
+		}
+		builtins[MiddlewareRequestID] = requestid.NewServerRequestIDInjector(
+			reqIDOpts...)
+		lg.Debug("enabled request ID injector")
 	}
 
 	// Configure logging.
 	if withReqLogging || withRepLogging {
-		// Automatically enable request ID injection if logging
-		// is enabled.
-		sp.Interceptors = append(sp.Interceptors,
-			requestid.NewServerRequestIDInjector())
-		log.Debug("enabled request ID injector")
-
 		var (
 			loggingOpts []logging.Option
-			w           = newLogger(log.Infof)
+			w           = newLogger(lg.Info)
 		)
 
 		if withDisableLogVolCtx {
 			loggingOpts = append(loggingOpts, logging.WithDisableLogVolumeContext())
-			log.Debug("disabled logging of VolumeContext field")
+			lg.Debug("disabled logging of VolumeContext field")
+		}
+
+		if strings.EqualFold(
+			csictx.Getenv(ctx, EnvVarLoggingFormat), "json") {
+			loggingOpts = append(loggingOpts, logging.WithJSONFormat())
+			lg.Debug("enabled json logging format")
+		}
+		if v := csictx.Getenv(ctx, EnvVarLoggingIncludeMethods); v != "" {
+			methods := utils.ParseSlice(v)
+			loggingOpts = append(loggingOpts,
+				logging.WithIncludeMethods(methods...))
+			lg.Debug("enabled logging include methods", "methods", methods)
+		}
+		if v := csictx.Getenv(ctx, EnvVarLoggingExcludeMethods); v != "" {
+			methods := utils.ParseSlice(v)
+			loggingOpts = append(loggingOpts,
+				logging.WithExcludeMethods(methods...))
+			lg.Debug("enabled logging exclude methods", "methods", methods)
+		}
+		if v := csictx.Getenv(ctx, EnvVarLoggingSampleRates); v != "" {
+			for method, szRate := range utils.ParseMap(v) {
+				rate, err := strconv.ParseFloat(strings.TrimSpace(szRate), 64)
+				if err != nil {
+					lg.Warn("invalid logging sample rate",
+						"method", method,
+						"rate", szRate)
+					continue
+				}
+				loggingOpts = append(loggingOpts,
+					logging.WithSampleRate(method, rate))
+				lg.Debug("enabled logging sample rate",
+					"method", method,
+					"rate", rate)
+			}
+		}
+		if v := csictx.Getenv(ctx, EnvVarLoggingRedactFields); v != "" {
+			fields := utils.ParseSlice(v)
+			loggingOpts = append(loggingOpts,
+				logging.WithRedactFields(fields...))
+			lg.Debug("enabled logging redaction", "fields", fields)
 		}
 
 		if withReqLogging {
 			loggingOpts = append(loggingOpts, logging.WithRequestLogging(w))
-			log.Debug("enabled request logging")
+			lg.Debug("enabled request logging")
 		}
 		if withRepLogging {
 			loggingOpts = append(loggingOpts, logging.WithResponseLogging(w))
-			log.Debug("enabled response logging")
+			lg.Debug("enabled response logging")
+
+			if v := csictx.Getenv(
+				ctx, EnvVarLoggingSlowCallThreshold); v != "" {
+				t, err := time.ParseDuration(v)
+				if err != nil {
+					lg.Warn("invalid slow call threshold",
+						"value", v,
+						"error", err)
+				} else {
+					loggingOpts = append(loggingOpts,
+						logging.WithSlowCallLogging(t, newLogger(lg.Warn)))
+					lg.Debug("enabled slow call logging", "threshold", t)
+				}
+			}
+		}
+		builtins[MiddlewareLogging] = logging.NewServerLogger(loggingOpts...)
+	}
+
+	if v := csictx.Getenv(ctx, EnvVarAuthzPolicy); v != "" {
+		p, err := authz.LoadPolicy(v)
+		if err != nil {
+			lg.Error("failed to load authz policy",
+				"path", v,
+				"error", err)
+			osExit(1)
+			return
 		}
-		sp.Interceptors = append(sp.Interceptors,
-			logging.NewServerLogger(loggingOpts...))
+		builtins[MiddlewareAuthz] = authz.NewServerAuthorizer(p)
+		lg.Debug("enabled authz", "policy", v)
+	}
+
+	if i := sp.newDeadlineEnforcer(ctx); i != nil {
+		builtins[MiddlewareDeadline] = i
 	}
 
 	if withSpecReq || withSpecRep {
@@ -133,92 +234,122 @@ func (sp *StoragePlugin) initInterceptors(ctx context.Context) {
 			specOpts = append(
 				specOpts,
 				specvalidator.WithRequestValidation())
-			log.Debug("enabled spec validator opt: request validation")
+			lg.Debug("enabled spec validator opt: request validation")
 		}
 		if withSpecRep {
 			specOpts = append(
 				specOpts,
 				specvalidator.WithResponseValidation())
-			log.Debug("enabled spec validator opt: response validation")
+			lg.Debug("enabled spec validator opt: response validation")
+		}
+		if withSpecReqWarnOnly {
+			specOpts = append(specOpts,
+				specvalidator.WithRequestWarnOnly())
+			lg.Debug("enabled spec validator opt: request warn only")
+		}
+		if withSpecRepWarnOnly {
+			specOpts = append(specOpts,
+				specvalidator.WithResponseWarnOnly())
+			lg.Debug("enabled spec validator opt: response warn only")
 		}
 		if withCredsNewVol {
 			specOpts = append(specOpts,
 				specvalidator.WithRequiresControllerCreateVolumeSecrets())
-			log.Debug("enabled spec validator opt: requires creds: " +
+			lg.Debug("enabled spec validator opt: requires creds: " +
 				"CreateVolume")
 		}
 		if withCredsDelVol {
 			specOpts = append(specOpts,
 				specvalidator.WithRequiresControllerDeleteVolumeSecrets())
-			log.Debug("enabled spec validator opt: requires creds: " +
+			lg.Debug("enabled spec validator opt: requires creds: " +
 				"DeleteVolume")
 		}
 		if withCredsCtrlrPubVol {
 			specOpts = append(specOpts,
 				specvalidator.WithRequiresControllerPublishVolumeSecrets())
-			log.Debug("enabled spec validator opt: requires creds: " +
+			lg.Debug("enabled spec validator opt: requires creds: " +
 				"ControllerPublishVolume")
 		}
 		if withCredsCtrlrUnpubVol {
 			specOpts = append(specOpts,
 				specvalidator.WithRequiresControllerUnpublishVolumeSecrets())
-			log.Debug("enabled spec validator opt: requires creds: " +
+			lg.Debug("enabled spec validator opt: requires creds: " +
 				"ControllerUnpublishVolume")
 		}
 		if withCredsNodeStgVol {
 			specOpts = append(specOpts,
 				specvalidator.WithRequiresNodeStageVolumeSecrets())
-			log.Debug("enabled spec validator opt: requires creds: " +
+			lg.Debug("enabled spec validator opt: requires creds: " +
 				"NodeStageVolume")
 		}
 		if withCredsNodePubVol {
 			specOpts = append(specOpts,
 				specvalidator.WithRequiresNodePublishVolumeSecrets())
-			log.Debug("enabled spec validator opt: requires creds: " +
+			lg.Debug("enabled spec validator opt: requires creds: " +
 				"NodePublishVolume")
 		}
 
 		if withStgTgtPath {
 			specOpts = append(specOpts,
 				specvalidator.WithRequiresStagingTargetPath())
-			log.Debug("enabled spec validator opt: " +
+			lg.Debug("enabled spec validator opt: " +
 				"requires starging target path")
 		}
 		if withVolContext {
 			specOpts = append(specOpts,
 				specvalidator.WithRequiresVolumeContext())
-			log.Debug("enabled spec validator opt: requires vol context")
+			lg.Debug("enabled spec validator opt: requires vol context")
 		}
 		if withPubContext {
 			specOpts = append(specOpts,
 				specvalidator.WithRequiresPublishContext())
-			log.Debug("enabled spec validator opt: requires pub context")
+			lg.Debug("enabled spec validator opt: requires pub context")
 		}
 		if withDisableFieldLen {
 			specOpts = append(specOpts,
 				specvalidator.WithDisableFieldLenCheck())
-			log.Debug("disabled spec validator opt: field length check")
+			lg.Debug("disabled spec validator opt: field length check")
+		}
+		if v, ok := csictx.LookupEnv(ctx, EnvVarSpecFieldLimits); ok {
+			for name, szLimit := range utils.ParseMap(v) {
+				limit, err := strconv.Atoi(strings.TrimSpace(szLimit))
+				if err != nil || limit < 0 {
+					lg.Warn("invalid spec validator field limit",
+						"field", name,
+						"limit", szLimit)
+					continue
+				}
+				specOpts = append(specOpts,
+					specvalidator.WithFieldLimit(name, limit))
+				lg.Debug("enabled spec validator opt: field limit",
+					"field", name,
+					"limit", limit)
+			}
 		}
-		sp.Interceptors = append(sp.Interceptors,
-			specvalidator.NewServerSpecValidator(specOpts...))
+		builtins[MiddlewareSpecValidator] = specvalidator.NewServerSpecValidator(
+			specOpts...)
+	}
+
+	if i := sp.newRateLimiter(ctx); i != nil {
+		builtins[MiddlewareRateLimit] = i
 	}
 
 	if _, ok := csictx.LookupEnv(ctx, EnvVarPluginInfo); ok {
-		log.Debug("enabled GetPluginInfo interceptor")
-		sp.Interceptors = append(sp.Interceptors, sp.getPluginInfo)
+		lg.Debug("enabled GetPluginInfo interceptor")
+		builtins[MiddlewarePluginInfo] = sp.getPluginInfo
 	}
 
 	if withSerialVol {
 		var (
 			opts   []serialvolume.Option
-			fields = map[string]interface{}{}
+			fields []any
 		)
 
 		// Get serial provider's timeout.
 		if v, _ := csictx.LookupEnv(
 			ctx, EnvVarSerialVolAccessTimeout); v != "" {
 			if t, err := time.ParseDuration(v); err == nil {
-				fields["serialVol.timeout"] = t
+				fields = append(fields, "serialVol.timeout", t)
 				opts = append(opts, serialvolume.WithTimeout(t))
 			}
 		}
@@ -227,25 +358,346 @@ func (sp *StoragePlugin) initInterceptors(ctx context.Context) {
 		if csictx.Getenv(ctx, EnvVarSerialVolAccessEtcdEndpoints) != "" {
 			p, err := etcd.New(ctx, "", 0, nil)
 			if err != nil {
-				log.Fatal(err)
+				lg.Error("failed to create etcd lock provider", "error", err)
+				osExit(1)
+				return
 			}
 			opts = append(opts, serialvolume.WithLockProvider(p))
 		}
 
-		sp.Interceptors = append(sp.Interceptors, serialvolume.New(opts...))
-		log.WithFields(fields).Debug("enabled serial volume access")
+		builtins[MiddlewareSerialVolume] = serialvolume.New(opts...)
+		lg.Debug("enabled serial volume access", fields...)
+	}
+
+	// The idempotency interceptor is added after the serial volume
+	// access interceptor so that responses are cached and returned
+	// while holding the volume's lock.
+	if sp.getEnvBool(ctx, EnvVarIdempotency) {
+		var (
+			opts   []idempotency.Option
+			fields []any
+		)
+
+		if v := csictx.Getenv(ctx, EnvVarIdempotencyTTL); v != "" {
+			if t, err := time.ParseDuration(v); err == nil {
+				fields = append(fields, "idempotency.ttl", t)
+				opts = append(opts, idempotency.WithTTL(t))
+			}
+		}
+
+		if strings.EqualFold(
+			csictx.Getenv(ctx, EnvVarIdempotencyStore), "etcd") {
+			s, err := idemetcd.New(ctx, "", nil)
+			if err != nil {
+				lg.Error("failed to create etcd idempotency store",
+					"error", err)
+				osExit(1)
+				return
+			}
+			fields = append(fields, "idempotency.store", "etcd")
+			opts = append(opts, idempotency.WithStore(s))
+		}
+
+		builtins[MiddlewareIdempotency] = idempotency.New(opts...)
+		lg.Debug("enabled idempotency", fields...)
+	}
+
+	// The secrets resolver is added after the logging interceptors so
+	// that the resolved secrets are never logged.
+	if withSecrets := sp.getEnvBool(ctx, EnvVarSecretsResolution); withSecrets ||
+		len(sp.SecretsResolvers) > 0 {
+		var opts []secrets.Option
+		if !withSecrets {
+			// Only the SP's resolvers are used.
+			opts = append(opts,
+				secrets.WithResolver("file", nil),
+				secrets.WithResolver("env", nil))
+		} else {
+			// Files and environment variables may only be referenced
+			// if they are permitted explicitly.
+			if v := csictx.Getenv(ctx, EnvVarSecretsFileDirs); v != "" {
+				dirs := utils.ParseSlice(v)
+				opts = append(opts, secrets.WithResolver(
+					"file", secrets.NewFileResolver(dirs...)))
+				lg.Debug("enabled secrets file dirs", "dirs", dirs)
+			}
+			if v := csictx.Getenv(ctx, EnvVarSecretsEnvVars); v != "" {
+				names := utils.ParseSlice(v)
+				opts = append(opts, secrets.WithResolver(
+					"env", secrets.NewEnvResolver(names...)))
+				lg.Debug("enabled secrets env vars", "names", names)
+			}
+		}
+		for scheme, r := range sp.SecretsResolvers {
+			opts = append(opts, secrets.WithResolver(scheme, r))
+			lg.Debug("enabled secrets resolver", "scheme", scheme)
+		}
+		builtins[MiddlewareSecrets] = secrets.NewServerSecretsResolver(opts...)
+		lg.Debug("enabled secrets resolution")
+	}
+
+	// The fault injector is added after the other interceptors so that
+	// the injected faults appear to be returned by the SP.
+	szFaults, withFaults := csictx.LookupEnv(ctx, EnvVarFaults)
+	if withFaults || sp.getEnvBool(ctx, EnvVarFaultInjectionRPC) {
+		var faults []faultinject.Fault
+		if withFaults {
+			var err error
+			if faults, err = faultinject.ParseFaults([]byte(szFaults)); err != nil {
+				lg.Error("failed to parse faults", "error", err)
+				osExit(1)
+				return
+			}
+		}
+		inj, err := faultinject.NewInjector(faults...)
+		if err != nil {
+			lg.Error("failed to create fault injector", "error", err)
+			osExit(1)
+			return
+		}
+		sp.faultInjector = inj
+		builtins[MiddlewareFaultInject] = faultinject.NewServerFaultInjector(inj)
+		lg.Warn("enabled fault injection", "faults", len(faults))
+	}
+
+	// The panic recoverer follows only the context and request ID
+	// injectors so that it recovers from panics in the other middleware.
+	// A panic in the goroutine in which the deadline enforcer runs the
+	// handler is re-raised by the enforcer in the call's goroutine.
+	if !sp.getEnvBool(ctx, EnvVarDisablePanicRecovery) {
+		builtins[MiddlewareRecovery] = recovery.NewServerRecoverer()
+		lg.Debug("enabled panic recovery")
 	}
 
-	return
+	chain, err := orderMiddleware(
+		builtins, sp.Middleware, sp.MiddlewareOrder)
+	if err != nil {
+		lg.Error("invalid middleware", "error", err)
+		osExit(1)
+		return
+	}
+
+	// The SP's interceptors precede the middleware.
+	names := make([]string, 0, len(sp.Interceptors)+len(chain))
+	for i := range sp.Interceptors {
+		names = append(names, fmt.Sprintf("Interceptors[%d]", i))
+	}
+	for _, e := range chain {
+		sp.Interceptors = append(sp.Interceptors, e.i)
+		names = append(names, e.name)
+	}
+	lg.Debug("interceptor chain", "interceptors", names)
+}
+
+// initAuthzCreds configures the server to obtain the credentials of
+// the peers of UNIX socket connections if authorization is enabled. The
+// credentials set by the SP's ServerOpts, ex. TLS, are never replaced,
+// so peers are identified by those credentials instead.
+func (sp *StoragePlugin) initAuthzCreds(ctx context.Context, lis net.Listener) {
+	if csictx.Getenv(ctx, EnvVarAuthzPolicy) == "" ||
+		lis.Addr().Network() != "unix" {
+		return
+	}
+	lg := csictx.GetLogger(ctx)
+	if hasCreds(sp.ServerOpts) {
+		lg.Warn("authz peer credentials disabled: " +
+			"server credentials set by ServerOpts")
+		return
+	}
+	sp.ServerOpts = append(sp.ServerOpts,
+		grpc.Creds(authz.NewPeerCredentials()))
+	lg.Debug("enabled authz peer credentials")
+}
+
+// hasCreds returns a flag indicating whether the provided server options
+// set the server's transport credentials. gRPC does not expose the value
+// of an option, so an option returned by grpc.Creds is identified by the
+// function it applies, which is the same for all of them.
+func hasCreds(opts []grpc.ServerOption) bool {
+	creds := optionFunc(grpc.Creds(nil))
+	for _, o := range opts {
+		if f := optionFunc(o); f != 0 && f == creds {
+			return true
+		}
+	}
+	return false
+}
+
+// optionFunc returns the code pointer of the function applied by the
+// provided server option, or zero if the option is not a function.
+func optionFunc(o grpc.ServerOption) uintptr {
+	v := reflect.Indirect(reflect.ValueOf(o))
+	if v.Kind() != reflect.Struct || v.NumField() != 1 ||
+		v.Field(0).Kind() != reflect.Func {
+		return 0
+	}
+	return v.Field(0).Pointer()
+}
+
+func (sp *StoragePlugin) newDeadlineEnforcer(
+	ctx context.Context,
+) grpc.UnaryServerInterceptor {
+	var (
+		lg   = csictx.GetLogger(ctx)
+		opts []deadline.Option
+	)
+
+	parseTimeouts := func(name string, opt func(string, time.Duration) deadline.Option) {
+		v := csictx.Getenv(ctx, name)
+		if v == "" {
+			return
+		}
+		for method, szTimeout := range utils.ParseMap(v) {
+			t, err := time.ParseDuration(strings.TrimSpace(szTimeout))
+			if err != nil {
+				lg.Warn("invalid deadline timeout",
+					"method", method,
+					"timeout", szTimeout)
+				continue
+			}
+			opts = append(opts, opt(method, t))
+			lg.Debug("enabled deadline timeout",
+				"name", name,
+				"method", method,
+				"timeout", t)
+		}
+	}
+	parseTimeouts(EnvVarDeadlineDefaults, deadline.WithDefaultTimeout)
+	parseTimeouts(EnvVarDeadlineMax, deadline.WithMaxTimeout)
+
+	// Only add the interceptor if there are timeouts.
+	if len(opts) == 0 {
+		return nil
+	}
+
+	if v := csictx.Getenv(ctx, EnvVarDeadlineOrphanWarning); v != "" {
+		if t, err := time.ParseDuration(v); err == nil {
+			opts = append(opts, deadline.WithOrphanWarning(t))
+			lg.Debug("enabled deadline orphan warning", "after", t)
+		}
+	}
+
+	lg.Debug("enabled deadline enforcer")
+	return deadline.NewServerDeadlineEnforcer(opts...)
+}
+
+func (sp *StoragePlugin) newRateLimiter(
+	ctx context.Context,
+) grpc.UnaryServerInterceptor {
+	var (
+		lg   = csictx.GetLogger(ctx)
+		opts []ratelimit.Option
+	)
+
+	if v := csictx.Getenv(ctx, EnvVarRateLimitMaxInFlight); v != "" {
+		for method, szMax := range utils.ParseMap(v) {
+			n, err := strconv.Atoi(strings.TrimSpace(szMax))
+			if err != nil {
+				lg.Warn("invalid max in-flight limit",
+					"method", method,
+					"max", szMax)
+				continue
+			}
+			opts = append(opts, ratelimit.WithMaxInFlight(method, n))
+			lg.Debug("enabled max in-flight limit",
+				"method", method,
+				"max", n)
+		}
+	}
+
+	if v := csictx.Getenv(ctx, EnvVarRateLimitRates); v != "" {
+		for method, szRate := range utils.ParseMap(v) {
+			var (
+				burst  int
+				err    error
+				parts  = strings.SplitN(strings.TrimSpace(szRate), ":", 2)
+				rate   float64
+				szPart = parts[0]
+			)
+			if rate, err = strconv.ParseFloat(szPart, 64); err == nil &&
+				len(parts) > 1 {
+				burst, err = strconv.Atoi(parts[1])
+			}
+			if err != nil {
+				lg.Warn("invalid rate limit",
+					"method", method,
+					"rate", szRate)
+				continue
+			}
+			opts = append(opts, ratelimit.WithRateLimit(method, rate, burst))
+			lg.Debug("enabled rate limit",
+				"method", method,
+				"rate", rate,
+				"burst", burst)
+		}
+	}
+
+	// Only add the interceptor if there are limits.
+	if len(opts) == 0 {
+		return nil
+	}
+
+	if v := csictx.Getenv(ctx, EnvVarRateLimitWait); v != "" {
+		if strings.EqualFold(v, "deadline") {
+			opts = append(opts, ratelimit.WithWait(ratelimit.WaitForDeadline))
+			lg.Debug("enabled rate limit wait", "wait", v)
+		} else if t, err := time.ParseDuration(v); err == nil {
+			opts = append(opts, ratelimit.WithWait(t))
+			lg.Debug("enabled rate limit wait", "wait", t)
+		}
+	}
+
+	if v := csictx.Getenv(ctx, EnvVarRateLimitCode); v != "" {
+		for c := codes.OK; c <= codes.Unauthenticated; c++ {
+			if strings.EqualFold(c.String(), v) {
+				opts = append(opts, ratelimit.WithErrorCode(c))
+				lg.Debug("enabled rate limit error code", "code", c)
+				break
+			}
+		}
+	}
+
+	if v := csictx.Getenv(ctx, EnvVarRateLimitKeyParam); v != "" {
+		opts = append(opts, ratelimit.WithKeyParameter(v))
+		lg.Debug("enabled rate limit key parameter", "param", v)
+	}
+
+	lg.Debug("enabled rate limiter")
+	return ratelimit.NewServerRateLimiter(opts...)
 }
 
 func (sp *StoragePlugin) injectContext(
 	ctx context.Context,
 	req interface{},
-	_ *grpc.UnaryServerInfo,
+	info *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler,
 ) (interface{}, error) {
-	return handler(csictx.WithLookupEnv(ctx, sp.lookupEnv), req)
+	ctx = csictx.WithLookupEnv(ctx, sp.lookupEnv)
+
+	reqInfo := csictx.RequestInfo{
+		FullMethod: info.FullMethod,
+		StartTime:  time.Now(),
+	}
+	if m, ok := rpcs.LookupRequest(req); ok {
+		reqInfo.Service = m.Service
+		reqInfo.Method = m.Name
+		if id, ok := m.VolumeID(req); ok {
+			reqInfo.VolumeID = id
+		} else if id, ok := m.SourceVolumeID(req); ok {
+			reqInfo.VolumeID = id
+		}
+	}
+	ctx = csictx.WithRequestInfo(ctx, reqInfo)
+
+	// Inject the SP's logger with the request's method and, if any,
+	// the ID of the volume the request targets.
+	fields := []any{"method", info.FullMethod}
+	if reqInfo.VolumeID != "" {
+		fields = append(fields, "volumeID", reqInfo.VolumeID)
+	}
+	ctx = csictx.WithLogger(ctx, sp.getLogger(ctx).With(fields...))
+
+	return handler(ctx, req)
 }
 
 func (sp *StoragePlugin) getPluginInfo(
@@ -258,11 +710,8 @@ func (sp *StoragePlugin) getPluginInfo(
 		return handler(ctx, req)
 	}
 
-	_, service, method, err := rpcs.ParseMethod(info.FullMethod)
-	if err != nil {
-		return nil, err
-	}
-	if service != "Identity" || method != "GetPluginInfo" {
+	m, ok := rpcs.Lookup(info.FullMethod)
+	if !ok || m.Service != "Identity" || m.Name != "GetPluginInfo" {
 		return handler(ctx, req)
 	}
 
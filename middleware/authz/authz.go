@@ -0,0 +1,185 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package authz provides a server-side gRPC interceptor that authorizes
+// calls to CSI methods based on the identity of the caller.
+//
+// A caller's identities are derived from the verified certificate of an
+// mTLS connection or from the credentials of the process on the other
+// end of a UNIX socket. Identities are strings with one of the following
+// forms:
+//
+//   - "cn:NAME" - the common name of a client certificate
+//   - "dns:NAME" - a DNS name in a client certificate
+//   - "uri:URI" - a URI in a client certificate, ex. a SPIFFE ID
+//   - "uid:UID" - the user ID of a UNIX socket peer
+//   - "gid:GID" - the group ID of a UNIX socket peer
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	xctx "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	csictx "github.com/dell/gocsi/context"
+	"github.com/dell/gocsi/utils/rpcs"
+)
+
+// Any may be used in a Rule in place of an identity to match all callers,
+// including those without an identity.
+const Any = "*"
+
+// Policy maps caller identities to the CSI methods they may call. A call
+// is permitted if any of the policy's rules permit it.
+//
+// The following policy permits root to call all methods and permits the
+// user with ID 1000 to call the Identity service and NodeGetVolumeStats:
+//
+//	{
+//	  "rules": [
+//	    { "identities": ["uid:0"], "methods": ["*"] },
+//	    {
+//	      "identities": ["uid:1000"],
+//	      "methods": ["Identity/*", "NodeGetVolumeStats"]
+//	    }
+//	  ]
+//	}
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule permits callers with any of its identities to call any of its
+// methods. Please see rpcs.MethodNames for the names by which a method may
+// be specified, ex. "NodePublishVolume", "Node/*", or rpcs.AllMethods.
+type Rule struct {
+	Identities []string `json:"identities"`
+	Methods    []string `json:"methods"`
+}
+
+// ParsePolicy parses a JSON encoded policy.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid authz policy: %w", err)
+	}
+	return &p, nil
+}
+
+// LoadPolicy reads and parses the JSON encoded policy file at the
+// provided path.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+	return ParsePolicy(data)
+}
+
+// Permits returns a flag indicating whether a caller with the provided
+// identities may call the provided method.
+func (p *Policy) Permits(identities []string, fullMethod string) bool {
+	identities = append(slices.Clone(identities), Any)
+
+	for _, r := range p.Rules {
+		if matchAny(r.Identities, identities) &&
+			rpcs.MatchMethod(r.Methods, fullMethod) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAny returns a flag indicating whether any of the values in a are
+// also in b.
+func matchAny(a, b []string) bool {
+	return slices.ContainsFunc(a, func(v string) bool {
+		return slices.Contains(b, v)
+	})
+}
+
+// Identities returns the identities of the caller of the gRPC request
+// associated with the provided context.
+func Identities(ctx context.Context) []string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	var ids []string
+	switch info := p.AuthInfo.(type) {
+	case credentials.TLSInfo:
+		// Only a certificate that was verified against the server's
+		// client CAs identifies the caller.
+		if len(info.State.VerifiedChains) == 0 ||
+			len(info.State.VerifiedChains[0]) == 0 {
+			return nil
+		}
+		cert := info.State.VerifiedChains[0][0]
+		if cert.Subject.CommonName != "" {
+			ids = append(ids, "cn:"+cert.Subject.CommonName)
+		}
+		for _, n := range cert.DNSNames {
+			ids = append(ids, "dns:"+n)
+		}
+		for _, u := range cert.URIs {
+			ids = append(ids, "uri:"+u.String())
+		}
+	case PeerCredInfo:
+		ids = append(ids,
+			fmt.Sprintf("uid:%d", info.UID),
+			fmt.Sprintf("gid:%d", info.GID))
+	}
+	return ids
+}
+
+// NewServerAuthorizer returns a new UnaryServerInterceptor that rejects
+// calls that are not permitted by the provided policy with
+// codes.PermissionDenied.
+func NewServerAuthorizer(p *Policy) grpc.UnaryServerInterceptor {
+	return (&interceptor{policy: p}).handle
+}
+
+type interceptor struct {
+	policy *Policy
+}
+
+func (i *interceptor) handle(
+	ctx xctx.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	ids := Identities(ctx)
+	if !i.policy.Permits(ids, info.FullMethod) {
+		csictx.GetLogger(ctx).Warn("authz: permission denied",
+			"method", info.FullMethod,
+			"identities", ids)
+		return nil, status.Errorf(codes.PermissionDenied,
+			"permission denied: %s", info.FullMethod)
+	}
+	return handler(ctx, req)
+}
@@ -0,0 +1,148 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package authz
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const testPolicy = `{
+  "rules": [
+    { "identities": ["uid:0"], "methods": ["*"] },
+    {
+      "identities": ["uid:1000", "cn:node-tool"],
+      "methods": ["Identity/*", "NodeGetVolumeStats"]
+    },
+    { "identities": ["*"], "methods": ["/csi.v1.Identity/Probe"] }
+  ]
+}`
+
+func TestPolicyPermits(t *testing.T) {
+	p, err := ParsePolicy([]byte(testPolicy))
+	require.NoError(t, err)
+
+	tests := []struct {
+		ids    []string
+		method string
+		want   bool
+	}{
+		{[]string{"uid:0", "gid:0"}, "/csi.v1.Controller/DeleteVolume", true},
+		{[]string{"uid:1000"}, "/csi.v1.Identity/GetPluginInfo", true},
+		{[]string{"uid:1000"}, "/csi.v1.Node/NodeGetVolumeStats", true},
+		{[]string{"uid:1000"}, "/csi.v1.Controller/DeleteVolume", false},
+		{[]string{"uid:1000"}, "/csi.v1.Node/NodePublishVolume", false},
+		{[]string{"cn:node-tool"}, "/csi.v1.Identity/GetPluginInfo", true},
+		{[]string{"cn:other"}, "/csi.v1.Identity/GetPluginInfo", false},
+		{nil, "/csi.v1.Identity/Probe", true},
+		{nil, "/csi.v1.Controller/DeleteVolume", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, p.Permits(tt.ids, tt.method),
+			"%v %s", tt.ids, tt.method)
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(testPolicy), 0o600))
+	p, err := LoadPolicy(path)
+	require.NoError(t, err)
+	assert.Len(t, p.Rules, 3)
+
+	_, err = LoadPolicy(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+
+	_, err = ParsePolicy([]byte("{"))
+	assert.Error(t, err)
+}
+
+func TestIdentities(t *testing.T) {
+	assert.Empty(t, Identities(context.Background()))
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: PeerCredInfo{UID: 1000, GID: 100},
+	})
+	assert.Equal(t, []string{"uid:1000", "gid:100"}, Identities(ctx))
+
+	cert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "node-tool"},
+		DNSNames: []string{"node-1.example.com"},
+		URIs: []*url.URL{
+			{Scheme: "spiffe", Host: "example.com", Path: "/node-tool"},
+		},
+	}
+	ctx = peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}},
+	})
+	assert.Equal(t, []string{
+		"cn:node-tool",
+		"dns:node-1.example.com",
+		"uri:spiffe://example.com/node-tool",
+	}, Identities(ctx))
+
+	// Unverified certificates do not identify the caller.
+	ctx = peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+		}},
+	})
+	assert.Empty(t, Identities(ctx))
+}
+
+func TestAuthorizer(t *testing.T) {
+	p, err := ParsePolicy([]byte(testPolicy))
+	require.NoError(t, err)
+	i := NewServerAuthorizer(p)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: PeerCredInfo{UID: 1000, GID: 100},
+	})
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+		return &csi.DeleteVolumeResponse{}, nil
+	}
+
+	_, err = i(ctx, &csi.DeleteVolumeRequest{},
+		&grpc.UnaryServerInfo{FullMethod: "/csi.v1.Controller/DeleteVolume"},
+		handler)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	rep, err := i(ctx, &csi.NodeGetVolumeStatsRequest{},
+		&grpc.UnaryServerInfo{FullMethod: "/csi.v1.Node/NodeGetVolumeStats"},
+		handler)
+	assert.NoError(t, err)
+	assert.NotNil(t, rep)
+}
@@ -0,0 +1,95 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package authz
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/credentials"
+)
+
+const peerCredProtocol = "peercred"
+
+// PeerCredInfo is the AuthInfo of a connection to a UNIX socket. It
+// contains the credentials of the peer process at the time it connected.
+type PeerCredInfo struct {
+	credentials.CommonAuthInfo
+	PID int32
+	UID uint32
+	GID uint32
+}
+
+// AuthType returns the type of the AuthInfo.
+func (PeerCredInfo) AuthType() string {
+	return peerCredProtocol
+}
+
+// noPeerCredInfo is the AuthInfo of a connection whose peer credentials
+// are not available, ex. a TCP connection.
+type noPeerCredInfo struct {
+	credentials.CommonAuthInfo
+}
+
+func (noPeerCredInfo) AuthType() string {
+	return peerCredProtocol
+}
+
+// NewPeerCredentials returns TransportCredentials that obtain the
+// credentials of the peer process of a UNIX socket connection using
+// SO_PEERCRED. Connections are not encrypted. Peer credentials are only
+// supported on Linux; on other platforms and for other types of
+// connections the peer has no identity.
+func NewPeerCredentials() credentials.TransportCredentials {
+	return peerCreds{}
+}
+
+type peerCreds struct{}
+
+func (peerCreds) ClientHandshake(
+	_ context.Context, _ string, conn net.Conn,
+) (net.Conn, credentials.AuthInfo, error) {
+	return conn, noPeerCredInfo{credentials.CommonAuthInfo{
+		SecurityLevel: credentials.NoSecurity,
+	}}, nil
+}
+
+func (peerCreds) ServerHandshake(
+	conn net.Conn,
+) (net.Conn, credentials.AuthInfo, error) {
+	common := credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity}
+	info, ok := getPeerCred(conn)
+	if !ok {
+		return conn, noPeerCredInfo{common}, nil
+	}
+	info.CommonAuthInfo = common
+	return conn, info, nil
+}
+
+func (peerCreds) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: peerCredProtocol}
+}
+
+func (c peerCreds) Clone() credentials.TransportCredentials {
+	return c
+}
+
+func (peerCreds) OverrideServerName(string) error {
+	return nil
+}
@@ -0,0 +1,49 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package authz
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// getPeerCred returns the credentials of the peer of a UNIX socket
+// connection.
+func getPeerCred(conn net.Conn) (PeerCredInfo, bool) {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return PeerCredInfo{}, false
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return PeerCredInfo{}, false
+	}
+	var (
+		cred    *unix.Ucred
+		credErr error
+	)
+	if err := raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(
+			int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil || credErr != nil {
+		return PeerCredInfo{}, false
+	}
+	return PeerCredInfo{PID: cred.Pid, UID: cred.Uid, GID: cred.Gid}, true
+}
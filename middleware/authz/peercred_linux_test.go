@@ -0,0 +1,77 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package authz
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+type identityServer struct {
+	csi.UnimplementedIdentityServer
+}
+
+func (*identityServer) Probe(
+	_ context.Context, _ *csi.ProbeRequest,
+) (*csi.ProbeResponse, error) {
+	return &csi.ProbeResponse{}, nil
+}
+
+func TestPeerCredentials(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "csi.sock")
+	lis, err := net.Listen("unix", sock)
+	require.NoError(t, err)
+
+	// Only the current user may call Probe.
+	p := &Policy{Rules: []Rule{{
+		Identities: []string{fmt.Sprintf("uid:%d", os.Getuid())},
+		Methods:    []string{"Probe"},
+	}}}
+	srv := grpc.NewServer(
+		grpc.Creds(NewPeerCredentials()),
+		grpc.UnaryInterceptor(NewServerAuthorizer(p)))
+	csi.RegisterIdentityServer(srv, &identityServer{})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("unix://"+sock,
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := csi.NewIdentityClient(conn)
+
+	_, err = client.Probe(context.Background(), &csi.ProbeRequest{})
+	assert.NoError(t, err)
+
+	_, err = client.GetPluginInfo(context.Background(),
+		&csi.GetPluginInfoRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,235 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package deadline provides a server-side gRPC interceptor that applies
+// default and maximum deadlines to CSI methods and returns to the caller
+// when a deadline is exceeded even if the handler is still running.
+package deadline
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	xctx "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	csictx "github.com/dell/gocsi/context"
+	"github.com/dell/gocsi/utils/rpcs"
+)
+
+// Option configures the interceptor.
+type Option func(*opts)
+
+type opts struct {
+	defaults      map[string]time.Duration
+	maximums      map[string]time.Duration
+	orphanWarning time.Duration
+}
+
+// WithDefaultTimeout is an Option that sets the timeout applied to calls
+// to the provided method whose context does not have a deadline. Please
+// see rpcs.MethodNames for the names by which a method may be specified,
+// ex. "NodePublishVolume" or rpcs.AllMethods.
+func WithDefaultTimeout(method string, d time.Duration) Option {
+	return func(o *opts) {
+		if o.defaults == nil {
+			o.defaults = map[string]time.Duration{}
+		}
+		o.defaults[method] = d
+	}
+}
+
+// WithMaxTimeout is an Option that sets the maximum timeout of calls to
+// the provided method. Calls whose context has a later deadline, or no
+// deadline and no default timeout, are given the maximum timeout instead.
+func WithMaxTimeout(method string, d time.Duration) Option {
+	return func(o *opts) {
+		if o.maximums == nil {
+			o.maximums = map[string]time.Duration{}
+		}
+		o.maximums[method] = d
+	}
+}
+
+// WithOrphanWarning is an Option that logs a warning if a handler is still
+// running the provided amount of time after its call's deadline was
+// exceeded. A handler that continues to run after its deadline is
+// orphaned: the caller has received an error, but the handler's goroutine
+// and any resources it holds, ex. a volume lock, remain in use.
+func WithOrphanWarning(d time.Duration) Option {
+	return func(o *opts) {
+		o.orphanWarning = d
+	}
+}
+
+// NewServerDeadlineEnforcer returns a new UnaryServerInterceptor that
+// applies default and maximum deadlines to CSI methods. If a call's
+// deadline is exceeded before its handler returns then the caller
+// receives codes.DeadlineExceeded immediately, and the handler's overrun
+// is logged when the handler eventually returns.
+//
+// The handler runs in its own goroutine, so a panic in the handler is
+// recovered and re-raised in the call's goroutine, where the interceptors
+// that precede this one, ex. the panic recoverer, may handle it. A panic
+// in a handler whose call has already returned is logged.
+func NewServerDeadlineEnforcer(opts ...Option) grpc.UnaryServerInterceptor {
+	i := &interceptor{}
+
+	// Configure the interceptor's options.
+	for _, setOpt := range opts {
+		setOpt(&i.opts)
+	}
+
+	return i.handle
+}
+
+type interceptor struct {
+	opts opts
+}
+
+type result struct {
+	rep   interface{}
+	err   error
+	panic *handlerPanic
+}
+
+// handlerPanic is the value of a panic in a handler. It includes the
+// stack of the handler's goroutine, which is not the stack of the
+// goroutine in which the panic is re-raised.
+type handlerPanic struct {
+	value interface{}
+	stack []byte
+}
+
+// String returns the panic's value and the stack of the handler's
+// goroutine.
+func (p *handlerPanic) String() string {
+	return fmt.Sprintf("%v\n\nhandler goroutine stack:\n%s", p.value, p.stack)
+}
+
+func (i *interceptor) handle(
+	ctx xctx.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	// Apply the default timeout if the call has no deadline, and then
+	// shorten the deadline to the maximum timeout.
+	if _, ok := ctx.Deadline(); !ok {
+		if d, ok := rpcs.LookupMethod(i.opts.defaults, info.FullMethod); ok && d > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, d)
+			defer cancel()
+		}
+	}
+	if d, ok := rpcs.LookupMethod(i.opts.maximums, info.FullMethod); ok && d > 0 {
+		if dl, ok := ctx.Deadline(); !ok || time.Until(dl) > d {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, d)
+			defer cancel()
+		}
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	// The handler is run in its own goroutine so that the caller does not
+	// have to wait for a handler that ignores its context.
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{panic: &handlerPanic{
+					value: r, stack: debug.Stack(),
+				}}
+			}
+		}()
+		rep, err := handler(ctx, req)
+		done <- result{rep: rep, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.panic != nil {
+			panic(res.panic)
+		}
+		if time.Now().After(deadline) {
+			csictx.GetLogger(ctx).Warn("handler overran deadline",
+				"method", info.FullMethod,
+				"overrun", time.Since(deadline))
+		}
+		return res.rep, res.err
+	case <-ctx.Done():
+		go i.watchOrphan(ctx, info.FullMethod, deadline, done)
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// watchOrphan waits for a handler that is still running after its call's
+// context is done. If the call's deadline was exceeded then the handler's
+// overrun is logged when it returns. A call canceled by the client is not
+// an overrun, so the handler's return is only logged at the debug level.
+// A panic in the handler is always logged.
+func (i *interceptor) watchOrphan(
+	ctx context.Context,
+	method string,
+	deadline time.Time,
+	done <-chan result,
+) {
+	lg := csictx.GetLogger(ctx)
+	overran := ctx.Err() == context.DeadlineExceeded
+
+	var warn <-chan time.Time
+	if overran && i.opts.orphanWarning > 0 {
+		t := time.NewTimer(i.opts.orphanWarning)
+		defer t.Stop()
+		warn = t.C
+	}
+
+	for {
+		select {
+		case <-warn:
+			lg.Warn("orphaned handler still running",
+				"method", method,
+				"overrun", time.Since(deadline))
+			warn = nil
+		case res := <-done:
+			switch {
+			case res.panic != nil:
+				lg.Error("orphaned handler panicked",
+					"method", method,
+					"panic", res.panic.String())
+			case overran:
+				lg.Warn("handler overran deadline",
+					"method", method,
+					"overrun", time.Since(deadline),
+					"error", res.err)
+			default:
+				lg.Debug("handler returned after call was canceled",
+					"method", method,
+					"error", res.err)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,233 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package deadline
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	csictx "github.com/dell/gocsi/context"
+	"github.com/dell/gocsi/middleware/internal/mwtest"
+	"github.com/dell/gocsi/utils/rpcs"
+)
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent use.
+type syncBuffer struct {
+	sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.String()
+}
+
+func newLoggerContext() (context.Context, *syncBuffer) {
+	buf := &syncBuffer{}
+	lg := slog.New(slog.NewTextHandler(buf, nil))
+	return csictx.WithLogger(context.Background(), lg), buf
+}
+
+func TestDefaultTimeout(t *testing.T) {
+	i := NewServerDeadlineEnforcer(
+		WithDefaultTimeout("CreateVolume", time.Minute))
+
+	var deadline time.Time
+	var ok bool
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		deadline, ok = ctx.Deadline()
+		return &csi.CreateVolumeResponse{}, nil
+	}
+
+	// A call without a deadline is given the default timeout.
+	_, err := i(context.Background(), &csi.CreateVolumeRequest{},
+		mwtest.CreateVolumeInfo, handler)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+
+	// A call with a deadline keeps it.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	_, err = i(ctx, &csi.CreateVolumeRequest{}, mwtest.CreateVolumeInfo, handler)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Second)
+
+	// Other methods are not given a deadline.
+	_, err = i(context.Background(), &csi.DeleteVolumeRequest{},
+		&grpc.UnaryServerInfo{FullMethod: "/csi.v1.Controller/DeleteVolume"},
+		handler)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestMaxTimeout(t *testing.T) {
+	i := NewServerDeadlineEnforcer(
+		WithDefaultTimeout(rpcs.AllMethods, time.Hour),
+		WithMaxTimeout("Controller/CreateVolume", time.Minute))
+
+	var deadline time.Time
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		deadline, _ = ctx.Deadline()
+		return &csi.CreateVolumeResponse{}, nil
+	}
+
+	// The default timeout is shortened to the maximum.
+	_, err := i(context.Background(), &csi.CreateVolumeRequest{},
+		mwtest.CreateVolumeInfo, handler)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+
+	// A shorter deadline is kept.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = i(ctx, &csi.CreateVolumeRequest{}, mwtest.CreateVolumeInfo, handler)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, time.Second)
+}
+
+func TestDeadlineExceeded(t *testing.T) {
+	ctx, buf := newLoggerContext()
+	i := NewServerDeadlineEnforcer(
+		WithDefaultTimeout(rpcs.AllMethods, 10*time.Millisecond),
+		WithOrphanWarning(10*time.Millisecond))
+
+	release := make(chan struct{})
+	returned := make(chan struct{})
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+		defer close(returned)
+		<-release
+		return &csi.CreateVolumeResponse{}, nil
+	}
+
+	// The caller receives an error while the handler is still running.
+	_, err := i(ctx, &csi.CreateVolumeRequest{}, mwtest.CreateVolumeInfo, handler)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(),
+			"orphaned handler still running")
+	}, time.Second, time.Millisecond)
+
+	close(release)
+	<-returned
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(),
+			"handler overran deadline")
+	}, time.Second, time.Millisecond)
+}
+
+func TestCanceled(t *testing.T) {
+	buf := &syncBuffer{}
+	lg := slog.New(slog.NewTextHandler(buf,
+		&slog.HandlerOptions{Level: slog.LevelDebug}))
+	i := NewServerDeadlineEnforcer(
+		WithDefaultTimeout(rpcs.AllMethods, time.Minute),
+		WithOrphanWarning(time.Millisecond))
+
+	ctx, cancel := context.WithCancel(
+		csictx.WithLogger(context.Background(), lg))
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+		cancel()
+		time.Sleep(10 * time.Millisecond)
+		return &csi.CreateVolumeResponse{}, nil
+	}
+
+	_, err := i(ctx, &csi.CreateVolumeRequest{}, mwtest.CreateVolumeInfo, handler)
+	assert.Equal(t, codes.Canceled, status.Code(err))
+
+	// A canceled call is not an overrun.
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(),
+			"handler returned after call was canceled")
+	}, time.Second, time.Millisecond)
+	assert.NotContains(t, buf.String(), "overran")
+	assert.NotContains(t, buf.String(), "orphaned")
+}
+
+func TestHandlerError(t *testing.T) {
+	i := NewServerDeadlineEnforcer(WithDefaultTimeout(rpcs.AllMethods, time.Minute))
+
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+
+	_, err := i(context.Background(), &csi.CreateVolumeRequest{},
+		mwtest.CreateVolumeInfo, handler)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestHandlerPanic(t *testing.T) {
+	i := NewServerDeadlineEnforcer(WithDefaultTimeout(rpcs.AllMethods, time.Minute))
+
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+		panic("handler panic")
+	}
+
+	// The panic is re-raised in the caller's goroutine.
+	defer func() {
+		r := recover()
+		assert.NotNil(t, r)
+		assert.Contains(t, fmt.Sprint(r), "handler panic")
+		assert.Contains(t, fmt.Sprint(r), "handler goroutine stack:")
+	}()
+	_, _ = i(context.Background(), &csi.CreateVolumeRequest{},
+		mwtest.CreateVolumeInfo, handler)
+	t.Fatal("panic not re-raised")
+}
+
+func TestOrphanedHandlerPanic(t *testing.T) {
+	ctx, buf := newLoggerContext()
+	i := NewServerDeadlineEnforcer(
+		WithDefaultTimeout(rpcs.AllMethods, 10*time.Millisecond))
+
+	release := make(chan struct{})
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+		<-release
+		panic("handler panic")
+	}
+
+	// The panic of a handler whose call has returned is logged.
+	_, err := i(ctx, &csi.CreateVolumeRequest{}, mwtest.CreateVolumeInfo, handler)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	close(release)
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(),
+			`msg="orphaned handler panicked"`)
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,143 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package faultinject
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// ServiceName is the name of the FaultInjector debug service.
+const ServiceName = "gocsi.faultinject.v1.FaultInjector"
+
+const (
+	// SetFaultsMethod is the full name of the debug RPC that replaces the
+	// injector's faults. Its request is a google.protobuf.StringValue
+	// that contains a JSON encoded list of faults, please see ParseFaults,
+	// and its response is a google.protobuf.Empty.
+	SetFaultsMethod = "/" + ServiceName + "/SetFaults"
+
+	// GetFaultsMethod is the full name of the debug RPC that returns the
+	// injector's faults. Its request is a google.protobuf.Empty and its
+	// response is a google.protobuf.StringValue that contains a JSON
+	// encoded list of faults.
+	GetFaultsMethod = "/" + ServiceName + "/GetFaults"
+)
+
+// RegisterDebugService registers the FaultInjector debug service with the
+// provided gRPC server. The service allows clients to change the faults
+// of the provided Injector at runtime, ex.
+//
+//	conn.Invoke(ctx, faultinject.SetFaultsMethod,
+//		wrapperspb.String(`[{"method":"*","code":"UNAVAILABLE"}]`),
+//		&emptypb.Empty{})
+func RegisterDebugService(s *grpc.Server, i *Injector) {
+	s.RegisterService(&debugServiceDesc, i)
+}
+
+// debugService is the interface of the FaultInjector debug service.
+type debugService interface {
+	setFaults(context.Context, *wrapperspb.StringValue) (*emptypb.Empty, error)
+	getFaults(context.Context, *emptypb.Empty) (*wrapperspb.StringValue, error)
+}
+
+func (i *Injector) setFaults(
+	_ context.Context, req *wrapperspb.StringValue,
+) (*emptypb.Empty, error) {
+	faults, err := ParseFaults([]byte(req.GetValue()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := i.SetFaults(faults...); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (i *Injector) getFaults(
+	_ context.Context, _ *emptypb.Empty,
+) (*wrapperspb.StringValue, error) {
+	buf, err := json.Marshal(i.Faults())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return wrapperspb.String(string(buf)), nil
+}
+
+var debugServiceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*debugService)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetFaults",
+			Handler: func(
+				srv interface{},
+				ctx context.Context,
+				dec func(interface{}) error,
+				interceptor grpc.UnaryServerInterceptor,
+			) (interface{}, error) {
+				in := &wrapperspb.StringValue{}
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(debugService).setFaults(ctx, req.(*wrapperspb.StringValue))
+				}
+				if interceptor == nil {
+					return handler(ctx, in)
+				}
+				return interceptor(ctx, in, &grpc.UnaryServerInfo{
+					Server:     srv,
+					FullMethod: SetFaultsMethod,
+				}, handler)
+			},
+		},
+		{
+			MethodName: "GetFaults",
+			Handler: func(
+				srv interface{},
+				ctx context.Context,
+				dec func(interface{}) error,
+				interceptor grpc.UnaryServerInterceptor,
+			) (interface{}, error) {
+				in := &emptypb.Empty{}
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(debugService).getFaults(ctx, req.(*emptypb.Empty))
+				}
+				if interceptor == nil {
+					return handler(ctx, in)
+				}
+				return interceptor(ctx, in, &grpc.UnaryServerInfo{
+					Server:     srv,
+					FullMethod: GetFaultsMethod,
+				}, handler)
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
@@ -0,0 +1,312 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package faultinject provides a server-side gRPC interceptor that
+// injects faults into calls to CSI methods in order to test how drivers
+// and container orchestrators handle errors, latency, and lost responses.
+package faultinject
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	xctx "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	csictx "github.com/dell/gocsi/context"
+	"github.com/dell/gocsi/utils/rpcs"
+)
+
+// randFloat64 returns a random number in [0.0,1.0) and may be replaced
+// by tests.
+var randFloat64 = rand.Float64
+
+// Fault describes a fault injected into matching calls.
+type Fault struct {
+	// Method is the method into which the fault is injected. Please see
+	// rpcs.MethodNames for the names by which a method may be specified,
+	// ex. "NodePublishVolume" or rpcs.AllMethods.
+	Method string `json:"method"`
+
+	// VolumeID is an optional regular expression that the ID of the
+	// volume in the request must match.
+	VolumeID string `json:"volumeID,omitempty"`
+
+	// Name is an optional regular expression that the name in the
+	// request, ex. the name of the volume to create, must match.
+	Name string `json:"name,omitempty"`
+
+	// Probability is the probability, from 0 to 1, that the fault is
+	// injected into a matching call. A probability of zero means the
+	// fault is never injected. If nil, the fault is always injected.
+	Probability *float64 `json:"probability,omitempty"`
+
+	// Latency is the amount of time a matching call is delayed before
+	// it is handled.
+	Latency time.Duration `json:"-"`
+
+	// Code is the code of the error returned for a matching call instead
+	// of invoking the handler.
+	Code codes.Code `json:"code,omitempty"`
+
+	// Message is the message of the error returned for a matching call.
+	Message string `json:"message,omitempty"`
+
+	// DropResponse indicates the handler is invoked, but if it succeeds
+	// its response is dropped and an error is returned instead, as if
+	// the response was lost. The error's code is Code, or if Code is OK,
+	// codes.Unavailable.
+	DropResponse bool `json:"dropResponse,omitempty"`
+
+	volumeIDRX *regexp.Regexp
+	nameRX     *regexp.Regexp
+}
+
+type jsonFault Fault
+
+// MarshalJSON encodes the fault as JSON. The fault's latency is encoded
+// as a duration string, ex. "100ms".
+func (f Fault) MarshalJSON() ([]byte, error) {
+	v := struct {
+		jsonFault
+		Latency string `json:"latency,omitempty"`
+	}{jsonFault: jsonFault(f)}
+	if f.Latency > 0 {
+		v.Latency = f.Latency.String()
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes the fault from JSON.
+func (f *Fault) UnmarshalJSON(data []byte) error {
+	var v struct {
+		jsonFault
+		Latency string `json:"latency,omitempty"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*f = Fault(v.jsonFault)
+	if v.Latency != "" {
+		t, err := time.ParseDuration(v.Latency)
+		if err != nil {
+			return err
+		}
+		f.Latency = t
+	}
+	return nil
+}
+
+// compile validates the fault and compiles its regular expressions.
+func (f *Fault) compile() error {
+	if f.Method == "" {
+		return fmt.Errorf("fault has no method")
+	}
+	if p := f.Probability; p != nil && (*p < 0 || *p > 1) {
+		return fmt.Errorf("invalid fault probability: %v", *p)
+	}
+	var err error
+	if f.VolumeID != "" {
+		if f.volumeIDRX, err = regexp.Compile(f.VolumeID); err != nil {
+			return fmt.Errorf("invalid fault volume ID pattern: %w", err)
+		}
+	}
+	if f.Name != "" {
+		if f.nameRX, err = regexp.Compile(f.Name); err != nil {
+			return fmt.Errorf("invalid fault name pattern: %w", err)
+		}
+	}
+	return nil
+}
+
+// ParseFaults parses a JSON encoded list of faults, ex.
+//
+//	[
+//	  {
+//	    "method": "NodePublishVolume",
+//	    "volumeID": "^vol-1",
+//	    "probability": 0.5,
+//	    "code": "UNAVAILABLE"
+//	  },
+//	  { "method": "CreateVolume", "latency": "2s", "dropResponse": true }
+//	]
+func ParseFaults(data []byte) ([]Fault, error) {
+	var faults []Fault
+	if err := json.Unmarshal(data, &faults); err != nil {
+		return nil, fmt.Errorf("invalid faults: %w", err)
+	}
+	return faults, nil
+}
+
+// Injector injects faults into calls to CSI methods. Its faults may be
+// changed while it is in use.
+type Injector struct {
+	sync.RWMutex
+	faults []Fault
+}
+
+// NewInjector returns a new Injector with the provided faults.
+func NewInjector(faults ...Fault) (*Injector, error) {
+	i := &Injector{}
+	if err := i.SetFaults(faults...); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// SetFaults replaces the injector's faults. The first fault that matches
+// a call is injected into the call.
+func (i *Injector) SetFaults(faults ...Fault) error {
+	compiled := make([]Fault, len(faults))
+	for n, f := range faults {
+		if err := f.compile(); err != nil {
+			return err
+		}
+		compiled[n] = f
+	}
+	i.Lock()
+	defer i.Unlock()
+	i.faults = compiled
+	return nil
+}
+
+// Faults returns the injector's faults.
+func (i *Injector) Faults() []Fault {
+	i.RLock()
+	defer i.RUnlock()
+	return append([]Fault(nil), i.faults...)
+}
+
+// match returns the fault to inject into the provided call, if any.
+func (i *Injector) match(fullMethod string, req interface{}) (Fault, bool) {
+	i.RLock()
+	defer i.RUnlock()
+	if len(i.faults) == 0 {
+		return Fault{}, false
+	}
+	for _, f := range i.faults {
+		if !rpcs.MatchMethod([]string{f.Method}, fullMethod) {
+			continue
+		}
+		if f.volumeIDRX != nil && !f.volumeIDRX.MatchString(volumeID(req)) {
+			continue
+		}
+		if f.nameRX != nil && !f.nameRX.MatchString(name(req)) {
+			continue
+		}
+		if p := f.Probability; p != nil && randFloat64() >= *p {
+			continue
+		}
+		return f, true
+	}
+	return Fault{}, false
+}
+
+// volumeID returns the ID of the volume the request targets, or for
+// CreateSnapshot, the ID of the source volume.
+func volumeID(req interface{}) string {
+	m, ok := rpcs.LookupRequest(req)
+	if !ok {
+		return ""
+	}
+	if id, ok := m.VolumeID(req); ok {
+		return id
+	}
+	id, _ := m.SourceVolumeID(req)
+	return id
+}
+
+// name returns the name of the object the request creates.
+func name(req interface{}) string {
+	m, ok := rpcs.LookupRequest(req)
+	if !ok {
+		return ""
+	}
+	name, _ := m.ObjectName(req)
+	return name
+}
+
+// NewServerFaultInjector returns a new UnaryServerInterceptor that
+// injects the faults of the provided Injector into calls to CSI methods.
+// Calls to the methods of the FaultInjector debug service are never
+// affected.
+func NewServerFaultInjector(i *Injector) grpc.UnaryServerInterceptor {
+	return i.handle
+}
+
+func (i *Injector) handle(
+	ctx xctx.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if strings.HasPrefix(info.FullMethod, "/"+ServiceName+"/") {
+		return handler(ctx, req)
+	}
+	f, ok := i.match(info.FullMethod, req)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	csictx.GetLogger(ctx).Info("injecting fault",
+		"method", info.FullMethod,
+		"latency", f.Latency,
+		"code", f.Code,
+		"dropResponse", f.DropResponse)
+
+	if f.Latency > 0 {
+		t := time.NewTimer(f.Latency)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+
+	if f.DropResponse {
+		if _, err := handler(ctx, req); err != nil {
+			return nil, err
+		}
+		code := f.Code
+		if code == codes.OK {
+			code = codes.Unavailable
+		}
+		return nil, status.Error(code, f.message("injected fault: response dropped"))
+	}
+
+	if f.Code != codes.OK {
+		return nil, status.Error(f.Code, f.message("injected fault"))
+	}
+
+	return handler(ctx, req)
+}
+
+func (f Fault) message(def string) string {
+	if f.Message != "" {
+		return f.Message
+	}
+	return def
+}
@@ -0,0 +1,291 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package faultinject
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/dell/gocsi/middleware/internal/mwtest"
+	"github.com/dell/gocsi/utils/rpcs"
+)
+
+func TestInjectError(t *testing.T) {
+	inj, err := NewInjector(Fault{
+		Method:   "NodePublishVolume",
+		VolumeID: "^vol-1$",
+		Code:     codes.Unavailable,
+		Message:  "array offline",
+	})
+	require.NoError(t, err)
+	i := NewServerFaultInjector(inj)
+
+	var n int
+	_, err = i(context.Background(),
+		&csi.NodePublishVolumeRequest{VolumeId: "vol-1"},
+		mwtest.NodePublishVolumeInfo, mwtest.CountingHandler(&n, nil))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, "array offline", status.Convert(err).Message())
+	assert.Equal(t, 0, n)
+
+	// Requests that do not match are not affected.
+	_, err = i(context.Background(),
+		&csi.NodePublishVolumeRequest{VolumeId: "vol-2"},
+		mwtest.NodePublishVolumeInfo, mwtest.CountingHandler(&n, nil))
+	assert.NoError(t, err)
+	_, err = i(context.Background(),
+		&csi.CreateVolumeRequest{Name: "vol-1"},
+		mwtest.CreateVolumeInfo, mwtest.CountingHandler(&n, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
+
+func TestInjectService(t *testing.T) {
+	inj, err := NewInjector(
+		Fault{Method: "Node/*", Code: codes.Unavailable},
+		// Method names are case-sensitive.
+		Fault{Method: "createvolume", Code: codes.Unavailable},
+	)
+	require.NoError(t, err)
+	i := NewServerFaultInjector(inj)
+
+	var n int
+	_, err = i(context.Background(), &csi.NodePublishVolumeRequest{},
+		mwtest.NodePublishVolumeInfo, mwtest.CountingHandler(&n, nil))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = i(context.Background(), &csi.CreateVolumeRequest{},
+		mwtest.CreateVolumeInfo, mwtest.CountingHandler(&n, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestInjectName(t *testing.T) {
+	inj, err := NewInjector(Fault{
+		Method: "Controller/CreateVolume",
+		Name:   "^chaos-",
+		Code:   codes.ResourceExhausted,
+	})
+	require.NoError(t, err)
+	i := NewServerFaultInjector(inj)
+
+	var n int
+	_, err = i(context.Background(), &csi.CreateVolumeRequest{Name: "chaos-1"},
+		mwtest.CreateVolumeInfo, mwtest.CountingHandler(&n, nil))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	_, err = i(context.Background(), &csi.CreateVolumeRequest{Name: "vol-1"},
+		mwtest.CreateVolumeInfo, mwtest.CountingHandler(&n, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+// probability returns a pointer to the provided probability.
+func probability(p float64) *float64 {
+	return &p
+}
+
+func TestInjectProbability(t *testing.T) {
+	defer func(f func() float64) { randFloat64 = f }(randFloat64)
+
+	inj, err := NewInjector(Fault{
+		Method:      rpcs.AllMethods,
+		Probability: probability(0.25),
+		Code:        codes.Internal,
+	})
+	require.NoError(t, err)
+	i := NewServerFaultInjector(inj)
+
+	var n int
+	randFloat64 = func() float64 { return 0.2 }
+	_, err = i(context.Background(), &csi.NodePublishVolumeRequest{},
+		mwtest.NodePublishVolumeInfo, mwtest.CountingHandler(&n, nil))
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	randFloat64 = func() float64 { return 0.3 }
+	_, err = i(context.Background(), &csi.NodePublishVolumeRequest{},
+		mwtest.NodePublishVolumeInfo, mwtest.CountingHandler(&n, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	// A probability of zero never injects the fault.
+	require.NoError(t, inj.SetFaults(Fault{
+		Method:      rpcs.AllMethods,
+		Probability: probability(0),
+		Code:        codes.Internal,
+	}))
+	randFloat64 = func() float64 { return 0 }
+	_, err = i(context.Background(), &csi.NodePublishVolumeRequest{},
+		mwtest.NodePublishVolumeInfo, mwtest.CountingHandler(&n, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
+
+func TestInjectLatency(t *testing.T) {
+	inj, err := NewInjector(Fault{
+		Method:  rpcs.AllMethods,
+		Latency: 20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	i := NewServerFaultInjector(inj)
+
+	var n int
+	start := time.Now()
+	_, err = i(context.Background(), &csi.NodePublishVolumeRequest{},
+		mwtest.NodePublishVolumeInfo, mwtest.CountingHandler(&n, nil))
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, 1, n)
+
+	// The latency does not exceed the call's deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err = i(ctx, &csi.NodePublishVolumeRequest{},
+		mwtest.NodePublishVolumeInfo, mwtest.CountingHandler(&n, nil))
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Equal(t, 1, n)
+}
+
+func TestInjectDropResponse(t *testing.T) {
+	inj, err := NewInjector(Fault{Method: rpcs.AllMethods, DropResponse: true})
+	require.NoError(t, err)
+	i := NewServerFaultInjector(inj)
+
+	// The handler is invoked but its response is dropped.
+	var n int
+	rep, err := i(context.Background(), &csi.NodePublishVolumeRequest{},
+		mwtest.NodePublishVolumeInfo,
+		mwtest.CountingHandler(&n, &csi.NodePublishVolumeResponse{}))
+	assert.Nil(t, rep)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 1, n)
+
+	// Errors from the handler are returned as is.
+	_, err = i(context.Background(), &csi.NodePublishVolumeRequest{},
+		mwtest.NodePublishVolumeInfo,
+		func(context.Context, interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "not found")
+		})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestParseFaults(t *testing.T) {
+	faults, err := ParseFaults([]byte(`[
+	  {
+	    "method": "NodePublishVolume",
+	    "volumeID": "^vol-1",
+	    "probability": 0.5,
+	    "code": "UNAVAILABLE"
+	  },
+	  { "method": "CreateVolume", "latency": "2s", "dropResponse": true }
+	]`))
+	require.NoError(t, err)
+	require.Len(t, faults, 2)
+	assert.Equal(t, codes.Unavailable, faults[0].Code)
+	assert.Equal(t, probability(0.5), faults[0].Probability)
+	assert.Equal(t, 2*time.Second, faults[1].Latency)
+	assert.True(t, faults[1].DropResponse)
+
+	// A fault without a probability is always injected, but one with a
+	// probability of zero is never injected.
+	assert.Nil(t, faults[1].Probability)
+	faults, err = ParseFaults([]byte(`[{"method": "*", "probability": 0}]`))
+	require.NoError(t, err)
+	assert.Equal(t, probability(0), faults[0].Probability)
+
+	_, err = ParseFaults([]byte(`[{"method": "*", "latency": "soon"}]`))
+	assert.Error(t, err)
+
+	_, err = NewInjector(Fault{Method: "*", VolumeID: "("})
+	assert.Error(t, err)
+	_, err = NewInjector(Fault{Method: "*", Probability: probability(2)})
+	assert.Error(t, err)
+	_, err = NewInjector(Fault{Code: codes.Internal})
+	assert.Error(t, err)
+}
+
+type identityServer struct {
+	csi.UnimplementedIdentityServer
+}
+
+func (*identityServer) Probe(
+	_ context.Context, _ *csi.ProbeRequest,
+) (*csi.ProbeResponse, error) {
+	return &csi.ProbeResponse{}, nil
+}
+
+func TestDebugService(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "csi.sock")
+	lis, err := net.Listen("unix", sock)
+	require.NoError(t, err)
+
+	inj, err := NewInjector()
+	require.NoError(t, err)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(NewServerFaultInjector(inj)))
+	csi.RegisterIdentityServer(srv, &identityServer{})
+	RegisterDebugService(srv, inj)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("unix://"+sock,
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	ctx := context.Background()
+	client := csi.NewIdentityClient(conn)
+
+	_, err = client.Probe(ctx, &csi.ProbeRequest{})
+	assert.NoError(t, err)
+
+	// A fault for all methods does not affect the debug service.
+	err = conn.Invoke(ctx, SetFaultsMethod,
+		wrapperspb.String(`[{"method": "*", "code": "UNAVAILABLE"}]`),
+		&emptypb.Empty{})
+	require.NoError(t, err)
+	_, err = client.Probe(ctx, &csi.ProbeRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	rep := &wrapperspb.StringValue{}
+	require.NoError(t, conn.Invoke(ctx, GetFaultsMethod, &emptypb.Empty{}, rep))
+	faults, err := ParseFaults([]byte(rep.GetValue()))
+	require.NoError(t, err)
+	require.Len(t, faults, 1)
+	assert.Equal(t, codes.Unavailable, faults[0].Code)
+
+	err = conn.Invoke(ctx, SetFaultsMethod,
+		wrapperspb.String(`[{"method": "*", "volumeID": "("}]`),
+		&emptypb.Empty{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	require.NoError(t, conn.Invoke(ctx, SetFaultsMethod,
+		wrapperspb.String(`[]`), &emptypb.Empty{}))
+	_, err = client.Probe(ctx, &csi.ProbeRequest{})
+	assert.NoError(t, err)
+}
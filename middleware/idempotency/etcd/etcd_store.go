@@ -0,0 +1,132 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"time"
+
+	etcd "go.etcd.io/etcd/client/v3"
+
+	csictx "github.com/dell/gocsi/context"
+	"github.com/dell/gocsi/middleware/idempotency"
+	svetcd "github.com/dell/gocsi/middleware/serialvolume/etcd"
+)
+
+// DefaultDomain is the default etcd key prefix under which the store's
+// entries are kept.
+const DefaultDomain = "/gocsi/idempotency"
+
+// New returns a new etcd idempotency store. If client is nil then a new
+// client is created using the same configuration, and environment
+// variables, as the serial volume access etcd lock provider.
+func New(
+	ctx context.Context,
+	domain string,
+	client *etcd.Client,
+) (idempotency.Store, error) {
+	if domain == "" {
+		domain = csictx.Getenv(ctx, EnvVarDomain)
+	}
+	if domain == "" {
+		domain = DefaultDomain
+	}
+	domain = path.Join("/", domain)
+
+	if client == nil {
+		config, err := svetcd.NewConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if client, err = etcd.New(config); err != nil {
+			return nil, err
+		}
+	}
+
+	csictx.GetLogger(ctx).Info("creating idempotency etcd store",
+		"idempotency.etcd.domain", domain)
+
+	return &store{client: client, domain: domain}, nil
+}
+
+type store struct {
+	client *etcd.Client
+	domain string
+}
+
+func (s *store) Close() error {
+	return s.client.Close()
+}
+
+func (s *store) key(key string) string {
+	return s.domain + "/" + key
+}
+
+func (s *store) Get(ctx context.Context, key string) (*idempotency.Entry, error) {
+	res, err := s.client.Get(ctx, s.key(key))
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Kvs) == 0 {
+		return nil, nil
+	}
+	var entry idempotency.Entry
+	if err := json.Unmarshal(res.Kvs[0].Value, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (s *store) Put(
+	ctx context.Context,
+	key string,
+	entry *idempotency.Entry,
+	ttl time.Duration,
+) error {
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Entries expire with a lease. A lease's TTL may not be less
+	// than one second.
+	secs := int64(ttl.Seconds())
+	if secs < 1 {
+		secs = 1
+	}
+	lease, err := s.client.Grant(ctx, secs)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.Put(
+		ctx, s.key(key), string(buf), etcd.WithLease(lease.ID))
+	return err
+}
+
+func (s *store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.Delete(ctx, s.key(key))
+	return err
+}
+
+func (s *store) DeletePrefix(ctx context.Context, prefix string) error {
+	_, err := s.client.Delete(ctx, s.key(prefix), etcd.WithPrefix())
+	return err
+}
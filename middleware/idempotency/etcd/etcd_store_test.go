@@ -0,0 +1,105 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package etcd
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	etcd "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+
+	"github.com/dell/gocsi/middleware/idempotency"
+)
+
+func startEtcd(t *testing.T) string {
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+
+	// Use ports that do not conflict with the serial volume access
+	// etcd tests, which may run at the same time.
+	clientURL := url.URL{Scheme: "http", Host: "127.0.0.1:23790"}
+	peerURL := url.URL{Scheme: "http", Host: "127.0.0.1:23800"}
+	cfg.ListenClientUrls = []url.URL{clientURL}
+	cfg.AdvertiseClientUrls = []url.URL{clientURL}
+	cfg.ListenPeerUrls = []url.URL{peerURL}
+	cfg.AdvertisePeerUrls = []url.URL{peerURL}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+
+	e, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(e.Close)
+
+	select {
+	case <-e.Server.ReadyNotify():
+	case <-time.After(30 * time.Second):
+		t.Fatal("etcd server took too long to start")
+	}
+	return clientURL.String()
+}
+
+func TestStore(t *testing.T) {
+	endpoint := startEtcd(t)
+	ctx := context.Background()
+
+	client, err := etcd.New(etcd.Config{
+		Endpoints:   []string{endpoint},
+		DialTimeout: 10 * time.Second,
+	})
+	assert.NoError(t, err)
+
+	s, err := New(ctx, "", client)
+	assert.NoError(t, err)
+	defer s.(io.Closer).Close()
+
+	entry := &idempotency.Entry{Hash: "abc", Response: []byte{1, 2, 3}}
+	assert.NoError(t, s.Put(ctx, "CreateVolume/a", entry, time.Minute))
+	assert.NoError(t, s.Put(ctx, "volumes/1",
+		&idempotency.Entry{Ref: "CreateVolume/a"}, time.Minute))
+
+	e, err := s.Get(ctx, "CreateVolume/a")
+	assert.NoError(t, err)
+	assert.Equal(t, entry, e)
+
+	e, err = s.Get(ctx, "CreateVolume/b")
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+
+	assert.NoError(t, s.DeletePrefix(ctx, "CreateVolume/"))
+	e, err = s.Get(ctx, "CreateVolume/a")
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+
+	assert.NoError(t, s.Delete(ctx, "volumes/1"))
+	e, err = s.Get(ctx, "volumes/1")
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+
+	// The entries are stored under the default domain.
+	res, err := client.Get(ctx, DefaultDomain, etcd.WithPrefix())
+	assert.NoError(t, err)
+	assert.Empty(t, res.Kvs)
+}
@@ -0,0 +1,342 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"time"
+
+	xctx "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+
+	csictx "github.com/dell/gocsi/context"
+	"github.com/dell/gocsi/utils/rpcs"
+)
+
+// DefaultTTL is the default amount of time for which a successful
+// response is cached.
+const DefaultTTL = 5 * time.Minute
+
+// Entry is a cached response.
+type Entry struct {
+	// Hash is the canonical hash of the request that produced the
+	// response.
+	Hash string `json:"hash,omitempty"`
+
+	// Response is the protobuf encoded response.
+	Response []byte `json:"response,omitempty"`
+
+	// Ref is the key of another entry. Entries with a reference are used
+	// to find a cached response by the ID of the object it created, ex.
+	// to invalidate a cached CreateVolume response when the volume is
+	// deleted.
+	Ref string `json:"ref,omitempty"`
+}
+
+// Store is able to store and retrieve cached responses.
+type Store interface {
+	// Get returns the entry with the provided key. A nil entry is
+	// returned if no entry exists or the entry has expired.
+	Get(ctx context.Context, key string) (*Entry, error)
+
+	// Put stores the entry with the provided key. The entry expires
+	// after the provided TTL.
+	Put(ctx context.Context, key string, entry *Entry, ttl time.Duration) error
+
+	// Delete removes the entry with the provided key.
+	Delete(ctx context.Context, key string) error
+
+	// DeletePrefix removes all entries with keys that begin with the
+	// provided prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// Option configures the interceptor.
+type Option func(*opts)
+
+type opts struct {
+	ttl   time.Duration
+	store Store
+}
+
+// WithTTL is an Option that sets the amount of time for which a
+// successful response is cached.
+func WithTTL(t time.Duration) Option {
+	return func(o *opts) {
+		o.ttl = t
+	}
+}
+
+// WithStore is an Option that sets the store used to cache responses.
+func WithStore(s Store) Option {
+	return func(o *opts) {
+		o.store = s
+	}
+}
+
+// New returns a new server-side, gRPC interceptor that caches the
+// successful responses of the following RPCs:
+//
+//   - CreateVolume
+//   - CreateSnapshot
+//   - ControllerPublishVolume
+//
+// A retry of a request that is identical to one whose response is cached
+// receives the cached response without invoking the handler. A request
+// that reuses the name of a cached request, or for ControllerPublishVolume
+// the volume and node IDs, but is otherwise different is rejected with
+// codes.AlreadyExists. Requests are compared using a canonical hash that
+// excludes their secrets.
+//
+// Cached responses are invalidated by a successful DeleteVolume,
+// DeleteSnapshot, or ControllerUnpublishVolume.
+func New(opts ...Option) grpc.UnaryServerInterceptor {
+	i := &interceptor{}
+
+	// Configure the interceptor's options.
+	for _, setOpt := range opts {
+		setOpt(&i.opts)
+	}
+
+	if i.opts.ttl <= 0 {
+		i.opts.ttl = DefaultTTL
+	}
+
+	// If no store is configured then set the default, in-memory store.
+	if i.opts.store == nil {
+		i.opts.store = NewMemoryStore()
+	}
+
+	return i.handle
+}
+
+type interceptor struct {
+	opts opts
+}
+
+func (i *interceptor) handle(
+	ctx xctx.Context,
+	req interface{},
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	m, ok := rpcs.LookupRequest(req)
+	if !ok {
+		return handler(ctx, req)
+	}
+	if m.Cached {
+		return i.cached(ctx, m, req, handler)
+	}
+	if len(m.Invalidates) > 0 {
+		return i.invalidate(ctx, m, req, handler)
+	}
+	return handler(ctx, req)
+}
+
+// cacheKey returns the key of the cached response to the request, ex.
+// "CreateVolume/<name>" or "ControllerPublishVolume/<volume>/<node>".
+func cacheKey(m *rpcs.Method, req interface{}) string {
+	if name, ok := m.ObjectName(req); ok {
+		return m.Name + "/" + url.PathEscape(name)
+	}
+	volID, _ := m.VolumeID(req)
+	key := volumeKey(m.Name, volID)
+	if nodeID, ok := m.NodeID(req); ok {
+		key += url.PathEscape(nodeID)
+	}
+	return key
+}
+
+// volumeKey returns the prefix of the keys of the cached responses to the
+// named RPC for the provided volume.
+func volumeKey(name, volID string) string {
+	return name + "/" + url.PathEscape(volID) + "/"
+}
+
+// refKey returns the key of a reference to a cached response by the ID
+// of the object of the provided kind, ex. "volumes/<id>".
+func refKey(kind, id string) string {
+	return kind + "s/" + url.PathEscape(id)
+}
+
+// cached returns the cached response for the request, if any, or invokes
+// the handler and caches its response. If the RPC creates an object then
+// a reference to the cached response by the object's ID is also cached
+// and is used to invalidate the response.
+func (i *interceptor) cached(
+	ctx context.Context,
+	m *rpcs.Method,
+	req interface{},
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	lg := csictx.GetLogger(ctx)
+	key := cacheKey(m, req)
+
+	hash, err := requestHash(m, req)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal,
+			"failed to hash request: %v", err)
+	}
+
+	// Errors from the store are logged and the request is handled as if
+	// no response was cached.
+	entry, err := i.opts.store.Get(ctx, key)
+	if err != nil {
+		lg.Warn("idempotency: failed to get cached response",
+			"key", key, "error", err)
+	}
+	if entry != nil {
+		if entry.Hash != hash {
+			return nil, status.Errorf(codes.AlreadyExists,
+				"request does not match previous request: %s", key)
+		}
+		rep := m.NewResponse()
+		err := proto.Unmarshal(entry.Response, protoadapt.MessageV2Of(
+			rep.(protoadapt.MessageV1)))
+		if err == nil {
+			lg.Debug("idempotency: returning cached response", "key", key)
+			return rep, nil
+		}
+		lg.Warn("idempotency: failed to decode cached response",
+			"key", key, "error", err)
+	}
+
+	res, err := handler(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	msg, ok := res.(protoadapt.MessageV1)
+	if !ok {
+		return res, nil
+	}
+	buf, err := proto.Marshal(protoadapt.MessageV2Of(msg))
+	if err != nil {
+		lg.Warn("idempotency: failed to encode response",
+			"key", key, "error", err)
+		return res, nil
+	}
+	entry = &Entry{Hash: hash, Response: buf}
+	if err := i.opts.store.Put(ctx, key, entry, i.opts.ttl); err != nil {
+		lg.Warn("idempotency: failed to cache response",
+			"key", key, "error", err)
+		return res, nil
+	}
+	if id, ok := m.CreatedID(res); ok {
+		ref := &Entry{Ref: key}
+		if err := i.opts.store.Put(
+			ctx, refKey(m.Creates, id), ref, i.opts.ttl); err != nil {
+			lg.Warn("idempotency: failed to cache response reference",
+				"key", key, "error", err)
+		}
+	}
+
+	return res, nil
+}
+
+// invalidate invokes the handler and, if the handler succeeds,
+// invalidates the cached responses of the RPCs the RPC invalidates.
+func (i *interceptor) invalidate(
+	ctx context.Context,
+	m *rpcs.Method,
+	req interface{},
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	res, err := handler(ctx, req)
+	if err != nil {
+		return res, err
+	}
+	for _, fullMethod := range m.Invalidates {
+		c, ok := rpcs.Lookup(fullMethod)
+		if !ok {
+			continue
+		}
+
+		// The response that created the deleted object.
+		if c.Creates != "" && c.Creates == m.Deletes {
+			id, _ := m.DeletedID(req)
+			i.deleteRef(ctx, refKey(c.Creates, id))
+			continue
+		}
+
+		// The responses for the request's volume. An empty or missing
+		// node ID invalidates the responses for all nodes.
+		volID, _ := m.VolumeID(req)
+		prefix := volumeKey(c.Name, volID)
+		if nodeID, _ := m.NodeID(req); nodeID != "" {
+			i.delete(ctx, prefix+url.PathEscape(nodeID))
+			continue
+		}
+		i.deletePrefix(ctx, prefix)
+	}
+	return res, nil
+}
+
+// deleteRef removes the reference with the provided key and the entry
+// to which it refers.
+func (i *interceptor) deleteRef(ctx context.Context, key string) {
+	ref, err := i.opts.store.Get(ctx, key)
+	if err != nil {
+		csictx.GetLogger(ctx).Warn("idempotency: failed to get reference",
+			"key", key, "error", err)
+		return
+	}
+	if ref == nil {
+		return
+	}
+	if ref.Ref != "" {
+		i.delete(ctx, ref.Ref)
+	}
+	i.delete(ctx, key)
+}
+
+func (i *interceptor) delete(ctx context.Context, key string) {
+	if err := i.opts.store.Delete(ctx, key); err != nil {
+		csictx.GetLogger(ctx).Warn("idempotency: failed to delete entry",
+			"key", key, "error", err)
+	}
+}
+
+func (i *interceptor) deletePrefix(ctx context.Context, prefix string) {
+	if err := i.opts.store.DeletePrefix(ctx, prefix); err != nil {
+		csictx.GetLogger(ctx).Warn("idempotency: failed to delete entries",
+			"prefix", prefix, "error", err)
+	}
+}
+
+// requestHash returns a canonical hash of the provided request. The
+// request's secrets are not included in the hash so that a retry with
+// rotated credentials is still considered identical.
+func requestHash(m *rpcs.Method, req interface{}) (string, error) {
+	msg := protoadapt.MessageV2Of(
+		m.WithSecrets(req, nil).(protoadapt.MessageV1))
+	buf, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:]), nil
+}
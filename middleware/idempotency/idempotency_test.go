@@ -0,0 +1,227 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dell/gocsi/middleware/internal/mwtest"
+)
+
+func TestCreateVolume(t *testing.T) {
+	i := New()
+	ctx := context.Background()
+	info := &grpc.UnaryServerInfo{}
+
+	var calls int
+	handler := mwtest.CountingHandler(&calls, &csi.CreateVolumeResponse{
+		Volume: &csi.Volume{VolumeId: "vol-1", CapacityBytes: 1024},
+	})
+
+	req := &csi.CreateVolumeRequest{
+		Name:       "test-volume",
+		Parameters: map[string]string{"pool": "gold"},
+		Secrets:    map[string]string{"password": "a"},
+	}
+
+	// The first request invokes the handler.
+	rep, err := i(ctx, req, info, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "vol-1", rep.(*csi.CreateVolumeResponse).Volume.VolumeId)
+	assert.Equal(t, 1, calls)
+
+	// An identical retry, even with rotated secrets, returns the cached
+	// response.
+	retry := &csi.CreateVolumeRequest{
+		Name:       "test-volume",
+		Parameters: map[string]string{"pool": "gold"},
+		Secrets:    map[string]string{"password": "b"},
+	}
+	rep, err = i(ctx, retry, info, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "vol-1", rep.(*csi.CreateVolumeResponse).Volume.VolumeId)
+	assert.Equal(t, int64(1024),
+		rep.(*csi.CreateVolumeResponse).Volume.CapacityBytes)
+	assert.Equal(t, 1, calls)
+
+	// A different request with the same name is rejected.
+	mismatch := &csi.CreateVolumeRequest{
+		Name:       "test-volume",
+		Parameters: map[string]string{"pool": "silver"},
+	}
+	_, err = i(ctx, mismatch, info, handler)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	assert.Equal(t, 1, calls)
+
+	// Deleting the volume invalidates the cached response.
+	_, err = i(ctx, &csi.DeleteVolumeRequest{VolumeId: "vol-1"}, info,
+		mwtest.CountingHandler(new(int), &csi.DeleteVolumeResponse{}))
+	assert.NoError(t, err)
+	_, err = i(ctx, mismatch, info, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestCreateSnapshot(t *testing.T) {
+	i := New()
+	ctx := context.Background()
+	info := &grpc.UnaryServerInfo{}
+
+	var calls int
+	handler := mwtest.CountingHandler(&calls, &csi.CreateSnapshotResponse{
+		Snapshot: &csi.Snapshot{SnapshotId: "snap-1", SourceVolumeId: "vol-1"},
+	})
+	req := &csi.CreateSnapshotRequest{Name: "snap", SourceVolumeId: "vol-1"}
+
+	for range 2 {
+		rep, err := i(ctx, req, info, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, "snap-1",
+			rep.(*csi.CreateSnapshotResponse).Snapshot.SnapshotId)
+	}
+	assert.Equal(t, 1, calls)
+
+	_, err := i(ctx,
+		&csi.CreateSnapshotRequest{Name: "snap", SourceVolumeId: "vol-2"},
+		info, handler)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = i(ctx, &csi.DeleteSnapshotRequest{SnapshotId: "snap-1"}, info,
+		mwtest.CountingHandler(new(int), &csi.DeleteSnapshotResponse{}))
+	assert.NoError(t, err)
+	_, err = i(ctx, req, info, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestControllerPublishVolume(t *testing.T) {
+	i := New()
+	ctx := context.Background()
+	info := &grpc.UnaryServerInfo{}
+
+	var calls int
+	handler := mwtest.CountingHandler(&calls, &csi.ControllerPublishVolumeResponse{
+		PublishContext: map[string]string{"lun": "1"},
+	})
+	req := &csi.ControllerPublishVolumeRequest{
+		VolumeId: "vol-1", NodeId: "node-1",
+	}
+
+	for range 2 {
+		_, err := i(ctx, req, info, handler)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, calls)
+
+	// A different node is a different request.
+	_, err := i(ctx, &csi.ControllerPublishVolumeRequest{
+		VolumeId: "vol-1", NodeId: "node-2",
+	}, info, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	// An incompatible publish to the same node is rejected.
+	_, err = i(ctx, &csi.ControllerPublishVolumeRequest{
+		VolumeId: "vol-1", NodeId: "node-1", Readonly: true,
+	}, info, handler)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	// Unpublishing from one node invalidates only that node's response.
+	unpub := mwtest.CountingHandler(new(int), &csi.ControllerUnpublishVolumeResponse{})
+	_, err = i(ctx, &csi.ControllerUnpublishVolumeRequest{
+		VolumeId: "vol-1", NodeId: "node-1",
+	}, info, unpub)
+	assert.NoError(t, err)
+	_, err = i(ctx, req, info, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	// Unpublishing from all nodes invalidates all of the responses.
+	_, err = i(ctx, &csi.ControllerUnpublishVolumeRequest{
+		VolumeId: "vol-1",
+	}, info, unpub)
+	assert.NoError(t, err)
+	_, err = i(ctx, req, info, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, calls)
+}
+
+func TestErrorNotCached(t *testing.T) {
+	i := New()
+	ctx := context.Background()
+	req := &csi.CreateVolumeRequest{Name: "test-volume"}
+
+	var calls int
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+		calls++
+		return nil, status.Error(codes.Unavailable, "busy")
+	}
+	for range 2 {
+		_, err := i(ctx, req, &grpc.UnaryServerInfo{}, handler)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	}
+	assert.Equal(t, 2, calls)
+}
+
+type failingStore struct {
+	Store
+}
+
+func (failingStore) Get(context.Context, string) (*Entry, error) {
+	return nil, errors.New("get failed")
+}
+
+func (failingStore) Put(context.Context, string, *Entry, time.Duration) error {
+	return errors.New("put failed")
+}
+
+func TestStoreErrors(t *testing.T) {
+	i := New(WithStore(failingStore{}))
+
+	var calls int
+	handler := mwtest.CountingHandler(&calls, &csi.CreateVolumeResponse{})
+	req := &csi.CreateVolumeRequest{Name: "test-volume"}
+	for range 2 {
+		_, err := i(context.Background(), req, &grpc.UnaryServerInfo{}, handler)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestPassThrough(t *testing.T) {
+	i := New()
+
+	var calls int
+	handler := mwtest.CountingHandler(&calls, &csi.ProbeResponse{})
+	for range 2 {
+		_, err := i(context.Background(), &csi.ProbeRequest{},
+			&grpc.UnaryServerInfo{}, handler)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls)
+}
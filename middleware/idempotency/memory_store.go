@@ -0,0 +1,103 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package idempotency
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the minimum amount of time between the removal of
+// expired entries from a memory store.
+const sweepInterval = time.Minute
+
+// timeNow returns the current time and may be replaced by tests.
+var timeNow = time.Now
+
+type memoryEntry struct {
+	entry   Entry
+	expires time.Time
+}
+
+type memoryStore struct {
+	sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+// NewMemoryStore returns a new, in-memory Store. Cached responses are
+// not shared between processes and do not survive a restart.
+func NewMemoryStore() Store {
+	return &memoryStore{entries: map[string]memoryEntry{}}
+}
+
+func (s *memoryStore) Get(_ context.Context, key string) (*Entry, error) {
+	s.Lock()
+	defer s.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	if !timeNow().Before(e.expires) {
+		delete(s.entries, key)
+		return nil, nil
+	}
+	entry := e.entry
+	return &entry, nil
+}
+
+func (s *memoryStore) Put(
+	_ context.Context, key string, entry *Entry, ttl time.Duration,
+) error {
+	s.Lock()
+	defer s.Unlock()
+	now := timeNow()
+	s.entries[key] = memoryEntry{entry: *entry, expires: now.Add(ttl)}
+
+	// Periodically remove the expired entries that have not been read.
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		for k, e := range s.entries {
+			if !now.Before(e.expires) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	return nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, key string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *memoryStore) DeletePrefix(_ context.Context, prefix string) error {
+	s.Lock()
+	defer s.Unlock()
+	for k := range s.entries {
+		if strings.HasPrefix(k, prefix) {
+			delete(s.entries, k)
+		}
+	}
+	return nil
+}
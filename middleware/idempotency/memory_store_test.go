@@ -0,0 +1,65 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	orig := timeNow
+	defer func() { timeNow = orig }()
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+
+	ctx := context.Background()
+	s := NewMemoryStore().(*memoryStore)
+
+	assert.NoError(t, s.Put(ctx, "a/1", &Entry{Hash: "1"}, time.Minute))
+	assert.NoError(t, s.Put(ctx, "a/2", &Entry{Hash: "2"}, time.Hour))
+	assert.NoError(t, s.Put(ctx, "b/1", &Entry{Ref: "a/1"}, time.Hour))
+
+	e, err := s.Get(ctx, "a/1")
+	assert.NoError(t, err)
+	assert.Equal(t, &Entry{Hash: "1"}, e)
+
+	// Expired entries are not returned and are eventually removed.
+	now = now.Add(2 * time.Minute)
+	e, err = s.Get(ctx, "a/1")
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+	assert.NoError(t, s.Put(ctx, "c/1", &Entry{}, time.Second))
+	now = now.Add(sweepInterval)
+	assert.NoError(t, s.Put(ctx, "c/2", &Entry{}, time.Hour))
+	assert.NotContains(t, s.entries, "c/1")
+
+	assert.NoError(t, s.DeletePrefix(ctx, "a/"))
+	e, err = s.Get(ctx, "a/2")
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+
+	assert.NoError(t, s.Delete(ctx, "b/1"))
+	e, err = s.Get(ctx, "b/1")
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+}
@@ -0,0 +1,48 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package mwtest provides the fixtures shared by the tests of the
+// middleware packages.
+package mwtest
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+var (
+	// CreateVolumeInfo is the server info of a CreateVolume call.
+	CreateVolumeInfo = &grpc.UnaryServerInfo{
+		FullMethod: "/csi.v1.Controller/CreateVolume",
+	}
+
+	// NodePublishVolumeInfo is the server info of a NodePublishVolume call.
+	NodePublishVolumeInfo = &grpc.UnaryServerInfo{
+		FullMethod: "/csi.v1.Node/NodePublishVolume",
+	}
+)
+
+// CountingHandler returns a handler that counts its invocations and
+// returns the provided response.
+func CountingHandler(n *int, rep interface{}) grpc.UnaryHandler {
+	return func(_ context.Context, _ interface{}) (interface{}, error) {
+		*n++
+		return rep, nil
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package logging
+
+import (
+	"math/rand/v2"
+	"strings"
+
+	"github.com/dell/gocsi/utils/rpcs"
+)
+
+// randFloat64 returns a pseudo-random number in [0.0,1.0) and may be
+// replaced by tests.
+var randFloat64 = rand.Float64
+
+// WithIncludeMethods is an Option that limits logging to the provided
+// methods. Please see rpcs.MethodNames for the names by which a method may
+// be specified, ex. "NodePublishVolume" or rpcs.AllMethods.
+func WithIncludeMethods(methods ...string) Option {
+	return func(o *opts) {
+		if o.includeMethods == nil {
+			o.includeMethods = map[string]struct{}{}
+		}
+		for _, m := range methods {
+			o.includeMethods[strings.TrimSpace(m)] = struct{}{}
+		}
+	}
+}
+
+// WithExcludeMethods is an Option that disables logging for the provided
+// methods. Exclusion takes precedence over inclusion.
+func WithExcludeMethods(methods ...string) Option {
+	return func(o *opts) {
+		if o.excludeMethods == nil {
+			o.excludeMethods = map[string]struct{}{}
+		}
+		for _, m := range methods {
+			o.excludeMethods[strings.TrimSpace(m)] = struct{}{}
+		}
+	}
+}
+
+// WithSampleRate is an Option that sets the fraction of calls to the
+// provided method that are logged, from 0.0 (none) to 1.0 (all).
+// rpcs.AllMethods sets the default rate. Calls that are not sampled are
+// still logged, request and response, if the call returns an error.
+func WithSampleRate(method string, rate float64) Option {
+	return func(o *opts) {
+		if o.sampleRates == nil {
+			o.sampleRates = map[string]float64{}
+		}
+		o.sampleRates[strings.TrimSpace(method)] = rate
+	}
+}
+
+// isMethodLogged returns a flag indicating whether the provided method
+// passes the include and exclude filters.
+func (s *interceptor) isMethodLogged(fullMethod string) bool {
+	if _, ok := rpcs.LookupMethod(s.opts.excludeMethods, fullMethod); ok {
+		return false
+	}
+	if len(s.opts.includeMethods) > 0 {
+		_, ok := rpcs.LookupMethod(s.opts.includeMethods, fullMethod)
+		return ok
+	}
+	return true
+}
+
+// isSampled returns a flag indicating whether a call to the provided
+// method is selected for logging by its sampling rate.
+func (s *interceptor) isSampled(fullMethod string) bool {
+	if len(s.opts.sampleRates) == 0 {
+		return true
+	}
+	rate, ok := rpcs.LookupMethod(s.opts.sampleRates, fullMethod)
+	if !ok {
+		return true
+	}
+	return rate >= 1 || (rate > 0 && randFloat64() < rate)
+}
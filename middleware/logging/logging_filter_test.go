@@ -0,0 +1,134 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dell/gocsi/utils/rpcs"
+)
+
+func TestIsMethodLogged(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		method string
+		want   bool
+	}{
+		{
+			name:   "no filters",
+			method: "/csi.v1.Identity/Probe",
+			want:   true,
+		},
+		{
+			name:   "excluded by name",
+			opts:   []Option{WithExcludeMethods("Probe")},
+			method: "/csi.v1.Identity/Probe",
+			want:   false,
+		},
+		{
+			name:   "included by service and name",
+			opts:   []Option{WithIncludeMethods("Controller/CreateVolume")},
+			method: "/csi.v1.Controller/CreateVolume",
+			want:   true,
+		},
+		{
+			name:   "not included",
+			opts:   []Option{WithIncludeMethods("CreateVolume")},
+			method: "/csi.v1.Identity/Probe",
+			want:   false,
+		},
+		{
+			name: "exclusion wins",
+			opts: []Option{
+				WithIncludeMethods("/csi.v1.Identity/Probe"),
+				WithExcludeMethods("Probe"),
+			},
+			method: "/csi.v1.Identity/Probe",
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := newLoggingInterceptor(tt.opts...)
+			assert.Equal(t, tt.want, i.isMethodLogged(tt.method))
+		})
+	}
+}
+
+func TestIsSampled(t *testing.T) {
+	orig := randFloat64
+	defer func() { randFloat64 = orig }()
+	randFloat64 = func() float64 { return 0.5 }
+
+	i := newLoggingInterceptor(
+		WithSampleRate("NodeGetVolumeStats", 0.1),
+		WithSampleRate("Probe", 0.9),
+		WithSampleRate(rpcs.AllMethods, 0),
+	)
+	assert.False(t, i.isSampled("/csi.v1.Node/NodeGetVolumeStats"))
+	assert.True(t, i.isSampled("/csi.v1.Identity/Probe"))
+	assert.False(t, i.isSampled("/csi.v1.Controller/CreateVolume"))
+
+	i = newLoggingInterceptor(WithSampleRate("Probe", 0))
+	assert.True(t, i.isSampled("/csi.v1.Controller/CreateVolume"))
+}
+
+func TestHandleNotSampled(t *testing.T) {
+	for _, json := range []bool{false, true} {
+		reqw := &bytes.Buffer{}
+		repw := &bytes.Buffer{}
+		opts := []Option{
+			WithRequestLogging(reqw),
+			WithResponseLogging(repw),
+			WithSampleRate(rpcs.AllMethods, 0),
+		}
+		if json {
+			opts = append(opts, WithJSONFormat())
+		}
+		i := newLoggingInterceptor(opts...)
+
+		// A successful call that is not sampled is not logged.
+		_, err := i.handle(context.Background(), "/csi.v1.Identity/Probe",
+			&csi.ProbeRequest{},
+			func() (interface{}, error) {
+				return &csi.ProbeResponse{}, nil
+			})
+		assert.NoError(t, err)
+		assert.Empty(t, reqw.String())
+		assert.Empty(t, repw.String())
+
+		// A failed call is always logged.
+		_, err = i.handle(context.Background(), "/csi.v1.Identity/Probe",
+			&csi.ProbeRequest{},
+			func() (interface{}, error) {
+				return nil, status.Error(codes.Internal, "failed")
+			})
+		assert.Error(t, err)
+		assert.Contains(t, reqw.String(), "/csi.v1.Identity/Probe")
+		assert.Contains(t, repw.String(), "Internal")
+	}
+}
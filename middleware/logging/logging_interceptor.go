@@ -26,9 +26,11 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 
 	csictx "github.com/dell/gocsi/context"
 	"github.com/dell/gocsi/utils/middleware"
@@ -41,6 +43,13 @@ type opts struct {
 	reqw             io.Writer
 	repw             io.Writer
 	disableLogVolCtx bool
+	json             bool
+	redactFields     [][]string
+	sloww            io.Writer
+	slowThreshold    time.Duration
+	includeMethods   map[string]struct{}
+	excludeMethods   map[string]struct{}
+	sampleRates      map[string]float64
 }
 
 // WithRequestLogging is a Option that enables request logging
@@ -73,6 +82,48 @@ func WithDisableLogVolumeContext() Option {
 	}
 }
 
+// WithSlowCallLogging is an Option that causes responses to RPCs that
+// take longer than the provided threshold to be written to w instead
+// of the response writer. This makes it possible to log slow calls at
+// a higher level, ex. WARN. Response logging must be enabled for this
+// option to have an effect.
+func WithSlowCallLogging(threshold time.Duration, w io.Writer) Option {
+	return func(o *opts) {
+		if w == nil {
+			w = os.Stdout
+		}
+		o.slowThreshold = threshold
+		o.sloww = w
+	}
+}
+
+// WithJSONFormat is an Option that causes the logging interceptor to emit
+// each request and response as a single JSON object. The CSI message is
+// encoded with protojson and fields matching the redaction policy are
+// replaced with RedactedValue.
+func WithJSONFormat() Option {
+	return func(o *opts) {
+		o.json = true
+	}
+}
+
+// WithRedactFields is an Option that adds one or more field paths to the
+// redaction policy used when logging in JSON format. A path is a series
+// of dot-separated protobuf field names or map keys, ex.
+// "parameters.password". Each segment may contain path.Match wildcards,
+// ex. "publish_context.*token*", and the segment "**" matches any number
+// of segments. Matching is case-insensitive. Fields named "secrets" are
+// always redacted.
+func WithRedactFields(fields ...string) Option {
+	return func(o *opts) {
+		for _, f := range fields {
+			if f = strings.TrimSpace(f); f != "" {
+				o.redactFields = append(o.redactFields, parseRedactField(f))
+			}
+		}
+	}
+}
+
 type interceptor struct {
 	opts opts
 }
@@ -98,6 +149,13 @@ func newLoggingInterceptor(opts ...Option) *interceptor {
 	for _, withOpts := range opts {
 		withOpts(&i.opts)
 	}
+	for _, f := range defaultRedactFields {
+		i.opts.redactFields = append(i.opts.redactFields, parseRedactField(f))
+	}
+	if i.opts.disableLogVolCtx {
+		i.opts.redactFields = append(i.opts.redactFields,
+			parseRedactField("**.volume_context"))
+	}
 	return i
 }
 
@@ -138,23 +196,51 @@ func (s *interceptor) handle(
 		return next()
 	}
 
+	// Skip logging entirely for filtered methods.
+	if !s.isMethodLogged(method) {
+		return next()
+	}
+
+	// If the call is not sampled then the request and response are
+	// only logged if the call fails.
+	sampled := s.isSampled(method)
+
+	if s.opts.json {
+		return s.handleJSON(ctx, method, req, sampled, next)
+	}
+
 	w := &bytes.Buffer{}
-	reqID, reqIDOK := csictx.GetRequestID(ctx)
+	reqID, reqIDOK := formatRequestID(ctx)
 
 	// Print the request
+	var reqLine string
 	if s.opts.reqw != nil {
 		fmt.Fprintf(w, "%s: ", method)
 		if reqIDOK {
-			fmt.Fprintf(w, "REQ %04d", reqID)
+			fmt.Fprintf(w, "REQ %s", reqID)
 		}
 		s.rprintReqOrRep(w, req)
-		fmt.Fprintln(s.opts.reqw, w.String())
+		reqLine = w.String()
+		if sampled {
+			fmt.Fprintln(s.opts.reqw, reqLine)
+		}
 	}
 
 	w.Reset()
 
 	// Get the response.
+	start := time.Now()
 	rep, failed = next()
+	duration := time.Since(start)
+
+	if !sampled {
+		if failed == nil {
+			return
+		}
+		if s.opts.reqw != nil {
+			fmt.Fprintln(s.opts.reqw, reqLine)
+		}
+	}
 
 	if s.opts.repw == nil {
 		return
@@ -163,9 +249,13 @@ func (s *interceptor) handle(
 	// Print the response method name.
 	fmt.Fprintf(w, "%s: ", method)
 	if reqIDOK {
-		fmt.Fprintf(w, "REP %04d", reqID)
+		fmt.Fprintf(w, "REP %s: ", reqID)
 	}
 
+	// Print the elapsed time and the response's gRPC code.
+	code := status.Code(failed)
+	fmt.Fprintf(w, "Duration=%s, Code=%s(%d)", duration, code, code)
+
 	// Print the response error if it is set.
 	if failed != nil {
 		fmt.Fprint(w, ": ")
@@ -176,11 +266,26 @@ func (s *interceptor) handle(
 	if !middleware.IsNilResponse(rep) {
 		s.rprintReqOrRep(w, rep)
 	}
-	fmt.Fprintln(s.opts.repw, w.String())
+	fmt.Fprintln(s.repWriter(duration), w.String())
 
 	return
 }
 
+// isSlow returns a flag indicating whether an RPC that took the provided
+// duration exceeds the slow call threshold.
+func (s *interceptor) isSlow(duration time.Duration) bool {
+	return s.opts.sloww != nil && duration > s.opts.slowThreshold
+}
+
+// repWriter returns the writer used to log a response to an RPC that
+// took the provided duration.
+func (s *interceptor) repWriter(duration time.Duration) io.Writer {
+	if s.isSlow(duration) {
+		return s.opts.sloww
+	}
+	return s.opts.repw
+}
+
 var emptyValRX = regexp.MustCompile(
 	`^((?:)|(?:\[\])|(?:<nil>)|(?:map\[\]))$`)
 
@@ -218,3 +323,13 @@ func (s *interceptor) rprintReqOrRep(w io.Writer, obj interface{}) {
 		fmt.Fprintf(w, "%s=%s", name, sv)
 	}
 }
+
+// formatRequestID returns the context's request ID formatted for the
+// text log. Numeric IDs are zero-padded to four digits and all other
+// IDs, ex. UUIDs, are returned as-is.
+func formatRequestID(ctx context.Context) (string, bool) {
+	if id, ok := csictx.GetRequestID(ctx); ok {
+		return fmt.Sprintf("%04d", id), true
+	}
+	return csictx.GetRequestIDString(ctx)
+}
@@ -23,13 +23,16 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	csictx "github.com/dell/gocsi/context"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestClientLogger(t *testing.T) {
@@ -199,3 +202,92 @@ func TestHandle(t *testing.T) {
 		})
 	}
 }
+
+func TestResponseDurationAndCode(t *testing.T) {
+	repw := &bytes.Buffer{}
+	i := newLoggingInterceptor(WithResponseLogging(repw))
+
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs(csictx.RequestIDKey, "7"))
+
+	_, err := i.handle(ctx, "/csi.v1.Node/NodeGetInfo",
+		&csi.NodeGetInfoRequest{},
+		func() (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "missing")
+		})
+	assert.Error(t, err)
+	assert.Regexp(t,
+		`^/csi.v1.Node/NodeGetInfo: REP 0007: Duration=\S+, Code=NotFound\(5\): `,
+		repw.String())
+}
+
+func TestStringRequestID(t *testing.T) {
+	reqw := &bytes.Buffer{}
+	repw := &bytes.Buffer{}
+	i := newLoggingInterceptor(
+		WithRequestLogging(reqw), WithResponseLogging(repw))
+
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs(csictx.RequestIDKey, "9b2b6e0e-3a8c-4b53"))
+
+	_, err := i.handle(ctx, "/csi.v1.Identity/Probe",
+		&csi.ProbeRequest{},
+		func() (interface{}, error) {
+			return &csi.ProbeResponse{}, nil
+		})
+	assert.NoError(t, err)
+	assert.Regexp(t,
+		`^/csi.v1.Identity/Probe: REQ 9b2b6e0e-3a8c-4b53`, reqw.String())
+	assert.Regexp(t,
+		`^/csi.v1.Identity/Probe: REP 9b2b6e0e-3a8c-4b53: `, repw.String())
+}
+
+func TestSlowCallLogging(t *testing.T) {
+	var (
+		repw  = &bytes.Buffer{}
+		sloww = &bytes.Buffer{}
+		opts  = []Option{
+			WithResponseLogging(repw),
+			WithSlowCallLogging(10*time.Millisecond, sloww),
+		}
+	)
+
+	sLogger := NewServerLogger(opts...)
+	info := &grpc.UnaryServerInfo{FullMethod: "/csi.v1.Identity/Probe"}
+
+	// A fast call is logged to the response writer.
+	_, err := sLogger(context.Background(), &csi.ProbeRequest{}, info,
+		func(_ context.Context, _ interface{}) (interface{}, error) {
+			return &csi.ProbeResponse{}, nil
+		})
+	assert.NoError(t, err)
+	assert.Contains(t, repw.String(), "Code=OK(0)")
+	assert.Empty(t, sloww.String())
+
+	// A slow call is logged to the slow call writer.
+	repw.Reset()
+	_, err = sLogger(context.Background(), &csi.ProbeRequest{}, info,
+		func(_ context.Context, _ interface{}) (interface{}, error) {
+			time.Sleep(20 * time.Millisecond)
+			return &csi.ProbeResponse{}, nil
+		})
+	assert.NoError(t, err)
+	assert.Empty(t, repw.String())
+	assert.Contains(t, sloww.String(), "/csi.v1.Identity/Probe")
+
+	// The client logger honors the same threshold.
+	sloww.Reset()
+	cLogger := NewClientLogger(opts...)
+	err = cLogger(context.Background(), "/csi.v1.Identity/Probe",
+		&csi.ProbeRequest{}, &csi.ProbeResponse{}, nil,
+		func(
+			_ context.Context, _ string, _, _ interface{},
+			_ *grpc.ClientConn, _ ...grpc.CallOption,
+		) error {
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Empty(t, repw.String())
+	assert.Contains(t, sloww.String(), "Code=OK(0)")
+}
@@ -0,0 +1,230 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/protoadapt"
+
+	csictx "github.com/dell/gocsi/context"
+	"github.com/dell/gocsi/utils/middleware"
+)
+
+// RedactedValue is the value that replaces the data of redacted fields
+// in JSON log records.
+const RedactedValue = "******"
+
+// defaultRedactFields are the field paths that are always redacted from
+// JSON log records.
+var defaultRedactFields = []string{"**.secrets"}
+
+// jsonRecord is a single JSON log record emitted for a request or
+// response.
+type jsonRecord struct {
+	Type       string          `json:"type"`
+	Method     string          `json:"method"`
+	RequestID  string          `json:"request_id,omitempty"`
+	DurationMS *float64        `json:"duration_ms,omitempty"`
+	Code       string          `json:"code,omitempty"`
+	CodeValue  *uint32         `json:"code_value,omitempty"`
+	Slow       bool            `json:"slow,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Message    json.RawMessage `json:"message,omitempty"`
+}
+
+func (s *interceptor) handleJSON(
+	ctx context.Context,
+	method string,
+	req interface{},
+	sampled bool,
+	next func() (interface{}, error),
+) (rep interface{}, failed error) {
+	reqID, _ := csictx.GetRequestIDString(ctx)
+
+	// Print the request.
+	reqRecord := jsonRecord{
+		Type:      "request",
+		Method:    method,
+		RequestID: reqID,
+	}
+	if s.opts.reqw != nil {
+		reqRecord.Message = s.marshalJSON(req)
+		if sampled {
+			s.writeJSON(s.opts.reqw, reqRecord)
+		}
+	}
+
+	// Get the response.
+	start := time.Now()
+	rep, failed = next()
+	elapsed := time.Since(start)
+
+	if !sampled {
+		if failed == nil {
+			return
+		}
+		if s.opts.reqw != nil {
+			s.writeJSON(s.opts.reqw, reqRecord)
+		}
+	}
+
+	if s.opts.repw == nil {
+		return
+	}
+
+	var (
+		duration  = float64(elapsed) / float64(time.Millisecond)
+		code      = status.Code(failed)
+		codeValue = uint32(code)
+	)
+	r := jsonRecord{
+		Type:       "response",
+		Method:     method,
+		RequestID:  reqID,
+		DurationMS: &duration,
+		Code:       code.String(),
+		CodeValue:  &codeValue,
+		Slow:       s.isSlow(elapsed),
+	}
+	if failed != nil {
+		r.Error = failed.Error()
+	}
+	if !middleware.IsNilResponse(rep) {
+		r.Message = s.marshalJSON(rep)
+	}
+	s.writeJSON(s.repWriter(elapsed), r)
+
+	return
+}
+
+func (s *interceptor) writeJSON(w io.Writer, r jsonRecord) {
+	buf, err := json.Marshal(r)
+	if err != nil {
+		fmt.Fprintf(w, "%s: failed to marshal log record: %v\n", r.Method, err)
+		return
+	}
+	fmt.Fprintln(w, string(buf))
+}
+
+// marshalJSON returns the JSON encoding of the provided request or
+// response with all fields that match the redaction policy replaced
+// by RedactedValue. Protobuf messages are encoded with protojson using
+// the field names defined in the CSI protobuf.
+func (s *interceptor) marshalJSON(obj interface{}) json.RawMessage {
+	var (
+		buf []byte
+		err error
+	)
+	if msg, ok := obj.(protoadapt.MessageV1); ok {
+		buf, err = protojson.MarshalOptions{
+			UseProtoNames: true,
+		}.Marshal(protoadapt.MessageV2Of(msg))
+	} else {
+		buf, err = json.Marshal(obj)
+	}
+	if err != nil {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.UseNumber()
+	var data interface{}
+	if err := dec.Decode(&data); err != nil {
+		return nil
+	}
+
+	data = redact(data, nil, s.opts.redactFields)
+
+	if buf, err = json.Marshal(data); err != nil {
+		return nil
+	}
+	return buf
+}
+
+// parseRedactField splits a field path such as "publish_context.*token*"
+// into its lower-case segments.
+func parseRedactField(field string) []string {
+	return strings.Split(strings.ToLower(strings.TrimSpace(field)), ".")
+}
+
+// redact walks the decoded JSON value v and replaces the values of all
+// object members whose path matches one of the provided patterns.
+// Arrays do not contribute a segment to the path, so the pattern
+// "entries.volume.volume_context" applies to every element of "entries".
+func redact(v interface{}, p []string, patterns [][]string) interface{} {
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		for k, mv := range tv {
+			kp := append(p[:len(p):len(p)], strings.ToLower(k))
+			if matchAnyField(patterns, kp) {
+				tv[k] = RedactedValue
+				continue
+			}
+			tv[k] = redact(mv, kp, patterns)
+		}
+	case []interface{}:
+		for i := range tv {
+			tv[i] = redact(tv[i], p, patterns)
+		}
+	}
+	return v
+}
+
+func matchAnyField(patterns [][]string, p []string) bool {
+	for _, patt := range patterns {
+		if matchField(patt, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchField returns a flag indicating whether the field path p matches
+// the pattern patt. Each segment of the pattern is matched using the
+// rules of path.Match, and a "**" segment matches zero or more segments.
+func matchField(patt, p []string) bool {
+	if len(patt) == 0 {
+		return len(p) == 0
+	}
+	if patt[0] == "**" {
+		for i := 0; i <= len(p); i++ {
+			if matchField(patt[1:], p[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(p) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(patt[0], p[0]); !ok {
+		return false
+	}
+	return matchField(patt[1:], p[1:])
+}
@@ -0,0 +1,164 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	csictx "github.com/dell/gocsi/context"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestHandleJSON(t *testing.T) {
+	reqw := &bytes.Buffer{}
+	repw := &bytes.Buffer{}
+
+	i := newLoggingInterceptor(
+		WithRequestLogging(reqw),
+		WithResponseLogging(repw),
+		WithJSONFormat(),
+		WithRedactFields("parameters.password", "publish_context.*token*"),
+	)
+
+	ctx := metadata.NewIncomingContext(
+		context.Background(),
+		metadata.Pairs(csictx.RequestIDKey, "42"))
+
+	req := &csi.CreateVolumeRequest{
+		Name:       "vol",
+		Parameters: map[string]string{"password": "p", "pool": "gold"},
+		Secrets:    map[string]string{"user": "u"},
+	}
+	rep := &csi.ControllerPublishVolumeResponse{
+		PublishContext: map[string]string{"authToken": "t", "lun": "1"},
+	}
+
+	res, err := i.handle(ctx, "/csi.v1.Controller/CreateVolume", req,
+		func() (interface{}, error) {
+			return rep, nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, rep, res)
+
+	assert.Equal(t, 1, strings.Count(reqw.String(), "\n"))
+	var reqRec map[string]interface{}
+	assert.NoError(t, json.Unmarshal(reqw.Bytes(), &reqRec))
+	assert.Equal(t, "request", reqRec["type"])
+	assert.Equal(t, "/csi.v1.Controller/CreateVolume", reqRec["method"])
+	assert.Equal(t, "42", reqRec["request_id"])
+	msg := reqRec["message"].(map[string]interface{})
+	assert.Equal(t, "vol", msg["name"])
+	assert.Equal(t, RedactedValue, msg["secrets"])
+	assert.Equal(t, map[string]interface{}{
+		"password": RedactedValue,
+		"pool":     "gold",
+	}, msg["parameters"])
+	assert.NotContains(t, reqw.String(), `"u"`)
+
+	var repRec map[string]interface{}
+	assert.NoError(t, json.Unmarshal(repw.Bytes(), &repRec))
+	assert.Equal(t, "response", repRec["type"])
+	assert.Equal(t, "OK", repRec["code"])
+	assert.Contains(t, repRec, "duration_ms")
+	assert.Equal(t, map[string]interface{}{
+		"authToken": RedactedValue,
+		"lun":       "1",
+	}, repRec["message"].(map[string]interface{})["publish_context"])
+}
+
+func TestHandleJSONError(t *testing.T) {
+	repw := &bytes.Buffer{}
+	i := newLoggingInterceptor(
+		WithResponseLogging(repw),
+		WithJSONFormat(),
+		WithDisableLogVolumeContext(),
+	)
+
+	_, err := i.handle(context.Background(), "/csi.v1.Node/NodePublishVolume",
+		&csi.NodePublishVolumeRequest{
+			VolumeContext: map[string]string{"k": "v"},
+		},
+		func() (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "no volume")
+		})
+	assert.Error(t, err)
+
+	var repRec map[string]interface{}
+	assert.NoError(t, json.Unmarshal(repw.Bytes(), &repRec))
+	assert.Equal(t, "NotFound", repRec["code"])
+	assert.Contains(t, repRec["error"], "no volume")
+	assert.NotContains(t, repRec, "message")
+	assert.NotContains(t, repRec, "request_id")
+}
+
+func TestMatchField(t *testing.T) {
+	tests := []struct {
+		patt string
+		path string
+		want bool
+	}{
+		{"secrets", "secrets", true},
+		{"secrets", "parameters.secrets", false},
+		{"**.secrets", "secrets", true},
+		{"**.secrets", "volume.nested.secrets", true},
+		{"parameters.password", "parameters.password", true},
+		{"parameters.password", "parameters.pool", false},
+		{"publish_context.*token*", "publish_context.authtoken", true},
+		{"publish_context.*token*", "publish_context", false},
+		{"**", "anything.at.all", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.patt+"~"+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchField(
+				parseRedactField(tt.patt), parseRedactField(tt.path)))
+		})
+	}
+}
+
+func TestHandleJSONSlowCall(t *testing.T) {
+	repw := &bytes.Buffer{}
+	sloww := &bytes.Buffer{}
+	i := newLoggingInterceptor(
+		WithResponseLogging(repw),
+		WithSlowCallLogging(0, sloww),
+		WithJSONFormat(),
+	)
+
+	_, err := i.handle(context.Background(), "/csi.v1.Identity/Probe",
+		&csi.ProbeRequest{},
+		func() (interface{}, error) {
+			return nil, status.Error(codes.Unavailable, "down")
+		})
+	assert.Error(t, err)
+	assert.Empty(t, repw.String())
+
+	var repRec map[string]interface{}
+	assert.NoError(t, json.Unmarshal(sloww.Bytes(), &repRec))
+	assert.Equal(t, true, repRec["slow"])
+	assert.Equal(t, "Unavailable", repRec["code"])
+	assert.Equal(t, float64(codes.Unavailable), repRec["code_value"])
+}
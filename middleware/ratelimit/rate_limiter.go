@@ -0,0 +1,343 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package ratelimit provides a server-side gRPC interceptor that limits
+// the number of in-flight calls to, and the rate of calls to, CSI methods.
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	xctx "golang.org/x/net/context"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dell/gocsi/utils/rpcs"
+)
+
+// WaitForDeadline may be used with WithWait so that calls wait until
+// their deadline for a limit to be satisfied.
+const WaitForDeadline time.Duration = -1
+
+// DefaultMaxKeys is the default maximum number of keys whose limits are
+// tracked separately. Please see WithMaxKeys.
+const DefaultMaxKeys = 1024
+
+// Option configures the interceptor.
+type Option func(*opts)
+
+type rateLimit struct {
+	limit rate.Limit
+	burst int
+}
+
+type opts struct {
+	maxInFlight map[string]int
+	rates       map[string]rateLimit
+	wait        time.Duration
+	code        codes.Code
+	keyParam    string
+	maxKeys     int
+}
+
+// WithMaxInFlight is an Option that limits the number of concurrent calls
+// to the provided method. Please see rpcs.MethodNames for the names by
+// which a method may be specified, ex. "NodePublishVolume" or
+// rpcs.AllMethods. A limit less than one disables the limit.
+func WithMaxInFlight(method string, n int) Option {
+	return func(o *opts) {
+		if o.maxInFlight == nil {
+			o.maxInFlight = map[string]int{}
+		}
+		o.maxInFlight[method] = n
+	}
+}
+
+// WithRateLimit is an Option that limits the rate of calls to the provided
+// method using a token bucket that is refilled at perSecond tokens per
+// second and holds at most burst tokens. If burst is less than one then
+// the bucket holds one second's worth of tokens.
+func WithRateLimit(method string, perSecond float64, burst int) Option {
+	return func(o *opts) {
+		if o.rates == nil {
+			o.rates = map[string]rateLimit{}
+		}
+		if burst < 1 {
+			burst = max(1, int(math.Ceil(perSecond)))
+		}
+		o.rates[method] = rateLimit{limit: rate.Limit(perSecond), burst: burst}
+	}
+}
+
+// WithWait is an Option that sets the amount of time a call waits for a
+// limit to be satisfied before it is rejected. The wait never exceeds the
+// call's deadline, and WaitForDeadline waits until the call's deadline.
+// By default calls that exceed a limit are rejected immediately.
+func WithWait(d time.Duration) Option {
+	return func(o *opts) {
+		o.wait = d
+	}
+}
+
+// WithErrorCode is an Option that sets the gRPC code of the error returned
+// for calls that exceed a limit. The default code is ResourceExhausted.
+func WithErrorCode(c codes.Code) Option {
+	return func(o *opts) {
+		o.code = c
+	}
+}
+
+// WithKeyParameter is an Option that applies the limits separately to each
+// value of the provided parameter, ex. the name of a storage array. The
+// value is read from a request's Parameters, or if the request does not
+// have parameters, its VolumeContext. Requests without the parameter share
+// the same limits.
+func WithKeyParameter(name string) Option {
+	return func(o *opts) {
+		o.keyParam = name
+	}
+}
+
+// WithMaxKeys is an Option that sets the maximum number of methods and
+// parameter values whose limits are tracked separately when
+// WithKeyParameter is used. Once the maximum is reached, the state of the
+// least recently
+// used value that has no in-flight calls and a full token bucket is
+// discarded, since its limits are the same as those of a new value. If
+// there is no such value then calls with a new value share the limits of
+// requests without the parameter. A maximum less than one uses
+// DefaultMaxKeys.
+func WithMaxKeys(n int) Option {
+	return func(o *opts) {
+		o.maxKeys = n
+	}
+}
+
+// NewServerRateLimiter returns a new UnaryServerInterceptor that limits
+// the number of in-flight calls to, and the rate of calls to, CSI methods.
+func NewServerRateLimiter(opts ...Option) grpc.UnaryServerInterceptor {
+	return newRateLimiter(opts...).handle
+}
+
+type interceptor struct {
+	opts opts
+
+	sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	nkeys   int
+}
+
+// entry is the state of the limits of a method, or of a method and a
+// value of the key parameter.
+type entry struct {
+	key   string
+	keyed bool
+	sem   chan struct{}
+	lim   *rate.Limiter
+
+	// refs is the number of calls that use the entry. An entry is not
+	// discarded while it is in use.
+	refs int
+}
+
+func newRateLimiter(opts ...Option) *interceptor {
+	i := &interceptor{
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+	}
+	i.opts.code = codes.ResourceExhausted
+	for _, setOpt := range opts {
+		setOpt(&i.opts)
+	}
+	if i.opts.maxKeys < 1 {
+		i.opts.maxKeys = DefaultMaxKeys
+	}
+	return i
+}
+
+// backendKey returns the value of the request's key parameter.
+func (i *interceptor) backendKey(req interface{}) string {
+	if i.opts.keyParam == "" {
+		return ""
+	}
+	switch treq := req.(type) {
+	case interface{ GetParameters() map[string]string }:
+		return treq.GetParameters()[i.opts.keyParam]
+	case interface{ GetVolumeContext() map[string]string }:
+		return treq.GetVolumeContext()[i.opts.keyParam]
+	}
+	return ""
+}
+
+// acquire returns the entry for the provided method and key parameter
+// value, creating it if necessary. The entry must be released once the
+// call is complete.
+func (i *interceptor) acquire(method, key string) *entry {
+	i.Lock()
+	defer i.Unlock()
+	if key != "" {
+		k := method + "/" + key
+		if el, ok := i.entries[k]; ok {
+			return i.use(el)
+		}
+		if i.nkeys < i.opts.maxKeys || i.evict() {
+			i.nkeys++
+			return i.use(i.lru.PushFront(&entry{key: k, keyed: true}))
+		}
+	}
+	if el, ok := i.entries[method]; ok {
+		return i.use(el)
+	}
+	return i.use(i.lru.PushFront(&entry{key: method}))
+}
+
+func (i *interceptor) use(el *list.Element) *entry {
+	e := el.Value.(*entry)
+	i.entries[e.key] = el
+	i.lru.MoveToFront(el)
+	e.refs++
+	return e
+}
+
+func (i *interceptor) release(e *entry) {
+	i.Lock()
+	defer i.Unlock()
+	e.refs--
+}
+
+// evict discards the least recently used entry of a key parameter value
+// that is not in use and whose token bucket is full. A flag is returned
+// indicating whether an entry was discarded.
+func (i *interceptor) evict() bool {
+	for el := i.lru.Back(); el != nil; el = el.Prev() {
+		e := el.Value.(*entry)
+		if !e.keyed || e.refs > 0 {
+			continue
+		}
+		if e.lim != nil && e.lim.Tokens() < float64(e.lim.Burst()) {
+			continue
+		}
+		i.lru.Remove(el)
+		delete(i.entries, e.key)
+		i.nkeys--
+		return true
+	}
+	return false
+}
+
+func (i *interceptor) semaphore(e *entry, n int) chan struct{} {
+	i.Lock()
+	defer i.Unlock()
+	if e.sem == nil {
+		e.sem = make(chan struct{}, n)
+	}
+	return e.sem
+}
+
+func (i *interceptor) limiter(e *entry, r rateLimit) *rate.Limiter {
+	i.Lock()
+	defer i.Unlock()
+	if e.lim == nil {
+		e.lim = rate.NewLimiter(r.limit, r.burst)
+	}
+	return e.lim
+}
+
+func (i *interceptor) handle(
+	ctx xctx.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	n, limitInFlight := rpcs.LookupMethod(i.opts.maxInFlight, info.FullMethod)
+	limitInFlight = limitInFlight && n > 0
+	r, limitRate := rpcs.LookupMethod(i.opts.rates, info.FullMethod)
+	if !limitInFlight && !limitRate {
+		return handler(ctx, req)
+	}
+
+	e := i.acquire(info.FullMethod, i.backendKey(req))
+	defer i.release(e)
+	key := e.key
+
+	// The context used to wait for a limit to be satisfied. If calls do
+	// not wait then the context is nil.
+	var wctx context.Context
+	switch {
+	case i.opts.wait > 0:
+		var cancel context.CancelFunc
+		wctx, cancel = context.WithTimeout(ctx, i.opts.wait)
+		defer cancel()
+	case i.opts.wait < 0:
+		wctx = ctx
+	}
+
+	// The in-flight limit is checked first so that a call rejected by
+	// it does not spend a token.
+	if limitInFlight {
+		sem := i.semaphore(e, n)
+		if wctx == nil {
+			select {
+			case sem <- struct{}{}:
+			default:
+				return nil, status.Errorf(i.opts.code,
+					"too many in-flight requests: %s", key)
+			}
+		} else {
+			select {
+			case sem <- struct{}{}:
+			case <-wctx.Done():
+				return nil, i.waitErr(ctx, "too many in-flight requests: %s", key)
+			}
+		}
+		defer func() { <-sem }()
+	}
+
+	if limitRate {
+		lim := i.limiter(e, r)
+		if wctx == nil {
+			if !lim.Allow() {
+				return nil, status.Errorf(i.opts.code,
+					"rate limit exceeded: %s", key)
+			}
+		} else if err := lim.Wait(wctx); err != nil {
+			return nil, i.waitErr(ctx, "rate limit exceeded: %s", key)
+		}
+	}
+
+	return handler(ctx, req)
+}
+
+// waitErr returns the error for a call that waited for a limit without
+// it being satisfied. If the call itself was cancelled or reached its
+// deadline then the corresponding error is returned instead.
+func (i *interceptor) waitErr(
+	ctx context.Context, format string, key string,
+) error {
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return status.Errorf(i.opts.code, format, key)
+}
@@ -0,0 +1,278 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dell/gocsi/middleware/internal/mwtest"
+	"github.com/dell/gocsi/utils/rpcs"
+)
+
+// blockingHandler returns a handler that signals when it is invoked and
+// blocks until release is closed.
+func blockingHandler(
+	started chan<- struct{}, release <-chan struct{},
+) grpc.UnaryHandler {
+	return func(_ context.Context, _ interface{}) (interface{}, error) {
+		started <- struct{}{}
+		<-release
+		return &csi.CreateVolumeResponse{}, nil
+	}
+}
+
+func okHandler(_ context.Context, _ interface{}) (interface{}, error) {
+	return &csi.CreateVolumeResponse{}, nil
+}
+
+func TestMaxInFlight(t *testing.T) {
+	i := NewServerRateLimiter(WithMaxInFlight("CreateVolume", 1))
+	ctx := context.Background()
+	req := &csi.CreateVolumeRequest{Name: "vol"}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		_, err := i(ctx, req, mwtest.CreateVolumeInfo,
+			blockingHandler(started, release))
+		done <- err
+	}()
+	<-started
+
+	// A second call is rejected while the first is in flight.
+	_, err := i(ctx, req, mwtest.CreateVolumeInfo, okHandler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// Other methods are not limited.
+	_, err = i(ctx, &csi.DeleteVolumeRequest{},
+		&grpc.UnaryServerInfo{FullMethod: "/csi.v1.Controller/DeleteVolume"},
+		okHandler)
+	assert.NoError(t, err)
+
+	close(release)
+	assert.NoError(t, <-done)
+
+	// The slot is released when the call completes.
+	_, err = i(ctx, req, mwtest.CreateVolumeInfo, okHandler)
+	assert.NoError(t, err)
+}
+
+func TestMaxInFlightWait(t *testing.T) {
+	i := NewServerRateLimiter(
+		WithMaxInFlight(rpcs.AllMethods, 1),
+		WithWait(WaitForDeadline),
+		WithErrorCode(codes.Unavailable))
+	req := &csi.CreateVolumeRequest{Name: "vol"}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		_, err := i(context.Background(), req, mwtest.CreateVolumeInfo,
+			blockingHandler(started, release))
+		done <- err
+	}()
+	<-started
+
+	// A call waits until its deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := i(ctx, req, mwtest.CreateVolumeInfo, okHandler)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	// A call that waits long enough proceeds once the slot is released.
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	_, err = i(context.Background(), req, mwtest.CreateVolumeInfo, okHandler)
+	assert.NoError(t, err)
+	assert.NoError(t, <-done)
+}
+
+func TestMaxInFlightWaitTimeout(t *testing.T) {
+	i := NewServerRateLimiter(
+		WithMaxInFlight(rpcs.AllMethods, 1),
+		WithWait(10*time.Millisecond),
+		WithErrorCode(codes.Unavailable))
+	req := &csi.CreateVolumeRequest{Name: "vol"}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	go i(context.Background(), req, mwtest.CreateVolumeInfo,
+		blockingHandler(started, release))
+	<-started
+
+	_, err := i(context.Background(), req, mwtest.CreateVolumeInfo, okHandler)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestRateLimit(t *testing.T) {
+	i := NewServerRateLimiter(WithRateLimit("Controller/CreateVolume", 1, 2))
+	ctx := context.Background()
+	req := &csi.CreateVolumeRequest{Name: "vol"}
+
+	for range 2 {
+		_, err := i(ctx, req, mwtest.CreateVolumeInfo, okHandler)
+		assert.NoError(t, err)
+	}
+	_, err := i(ctx, req, mwtest.CreateVolumeInfo, okHandler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestRateLimitWait(t *testing.T) {
+	i := NewServerRateLimiter(
+		WithRateLimit(rpcs.AllMethods, 100, 1),
+		WithWait(time.Second))
+	ctx := context.Background()
+	req := &csi.CreateVolumeRequest{Name: "vol"}
+
+	// The second call waits for a token.
+	for range 2 {
+		_, err := i(ctx, req, mwtest.CreateVolumeInfo, okHandler)
+		assert.NoError(t, err)
+	}
+
+	// A call whose deadline is before the next token is rejected.
+	i = NewServerRateLimiter(
+		WithRateLimit(rpcs.AllMethods, 0.1, 1),
+		WithWait(time.Second))
+	_, err := i(ctx, req, mwtest.CreateVolumeInfo, okHandler)
+	assert.NoError(t, err)
+	_, err = i(ctx, req, mwtest.CreateVolumeInfo, okHandler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestKeyParameter(t *testing.T) {
+	i := NewServerRateLimiter(
+		WithRateLimit("CreateVolume", 1, 1),
+		WithKeyParameter("array"))
+	ctx := context.Background()
+
+	newReq := func(array string) *csi.CreateVolumeRequest {
+		return &csi.CreateVolumeRequest{
+			Name:       "vol",
+			Parameters: map[string]string{"array": array},
+		}
+	}
+
+	_, err := i(ctx, newReq("a"), mwtest.CreateVolumeInfo, okHandler)
+	assert.NoError(t, err)
+	_, err = i(ctx, newReq("b"), mwtest.CreateVolumeInfo, okHandler)
+	assert.NoError(t, err)
+	_, err = i(ctx, newReq("a"), mwtest.CreateVolumeInfo, okHandler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestMaxInFlightDoesNotSpendToken(t *testing.T) {
+	i := NewServerRateLimiter(
+		WithMaxInFlight("CreateVolume", 1),
+		WithRateLimit("CreateVolume", 0.001, 2))
+	ctx := context.Background()
+	req := &csi.CreateVolumeRequest{Name: "vol"}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		_, err := i(ctx, req, mwtest.CreateVolumeInfo,
+			blockingHandler(started, release))
+		done <- err
+	}()
+	<-started
+
+	// The calls rejected by the in-flight limit do not spend the
+	// remaining token.
+	for range 3 {
+		_, err := i(ctx, req, mwtest.CreateVolumeInfo, okHandler)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.ErrorContains(t, err, "too many in-flight requests")
+	}
+	close(release)
+	assert.NoError(t, <-done)
+
+	_, err := i(ctx, req, mwtest.CreateVolumeInfo, okHandler)
+	assert.NoError(t, err)
+	_, err = i(ctx, req, mwtest.CreateVolumeInfo, okHandler)
+	assert.ErrorContains(t, err, "rate limit exceeded")
+}
+
+func TestMaxKeys(t *testing.T) {
+	i := newRateLimiter(
+		WithRateLimit("CreateVolume", 0.001, 1),
+		WithKeyParameter("array"),
+		WithMaxKeys(2))
+	ctx := context.Background()
+
+	call := func(array string) error {
+		_, err := i.handle(ctx, &csi.CreateVolumeRequest{
+			Name:       "vol",
+			Parameters: map[string]string{"array": array},
+		}, mwtest.CreateVolumeInfo, okHandler)
+		return err
+	}
+
+	// The entries of values whose bucket is not full are not discarded,
+	// so calls with new values share the limits of the method.
+	assert.NoError(t, call("a"))
+	assert.NoError(t, call("b"))
+	assert.NoError(t, call("c"))
+	assert.Equal(t, "rate limit exceeded: "+mwtest.CreateVolumeInfo.FullMethod,
+		status.Convert(call("d")).Message())
+	assert.Error(t, call("a"))
+	assert.Len(t, i.entries, 3)
+
+	// The entries of idle values with a full bucket are discarded.
+	i = newRateLimiter(
+		WithMaxInFlight("CreateVolume", 1),
+		WithKeyParameter("array"),
+		WithMaxKeys(2))
+	for _, array := range []string{"a", "b", "c", "d", "e"} {
+		assert.NoError(t, call(array))
+	}
+	assert.Len(t, i.entries, 2)
+	assert.Contains(t, i.entries, mwtest.CreateVolumeInfo.FullMethod+"/e")
+	assert.Contains(t, i.entries, mwtest.CreateVolumeInfo.FullMethod+"/d")
+}
+
+func TestBackendKey(t *testing.T) {
+	i := newRateLimiter(WithKeyParameter("array"))
+	assert.Equal(t, "a", i.backendKey(&csi.CreateVolumeRequest{
+		Parameters: map[string]string{"array": "a"},
+	}))
+	assert.Equal(t, "b", i.backendKey(&csi.ControllerPublishVolumeRequest{
+		VolumeContext: map[string]string{"array": "b"},
+	}))
+	assert.Equal(t, "", i.backendKey(&csi.ProbeRequest{}))
+
+	i = newRateLimiter()
+	assert.Equal(t, "", i.backendKey(&csi.CreateVolumeRequest{
+		Parameters: map[string]string{"array": "a"},
+	}))
+}
@@ -0,0 +1,90 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package recovery provides a server-side gRPC interceptor that recovers
+// from panics in CSI handlers.
+package recovery
+
+import (
+	"expvar"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/google/uuid"
+	xctx "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	csictx "github.com/dell/gocsi/context"
+)
+
+// Panics records the number of panics recovered by all recovery
+// interceptors in the process. The map is keyed by the full gRPC method
+// name, and is published via expvar as "gocsi.recovery.panics".
+var Panics = expvar.NewMap("gocsi.recovery.panics")
+
+// newID returns a new, unique request ID and may be replaced by tests.
+var newID = uuid.NewString
+
+type interceptor struct{}
+
+// NewServerRecoverer returns a new UnaryServerInterceptor that recovers
+// from a panic in the handler, or in the interceptors that follow it in
+// the chain. Panics in the interceptors that precede it in the chain, ex.
+// the StoragePlugin's Interceptors and the context and request ID
+// injectors, are not recovered, nor are panics in goroutines started by
+// the handler or the interceptors that follow it. The exception is the
+// goroutine in which the deadline enforcer runs the handler, whose panics
+// the enforcer re-raises in the call's goroutine. The panic and its
+// stack trace are logged, and the caller receives a codes.Internal error
+// that includes the request's ID so the error may be correlated with the
+// log. If the request does not have an ID, ex. because request ID
+// injection is disabled, then a new ID is generated for the panic.
+func NewServerRecoverer() grpc.UnaryServerInterceptor {
+	return (&interceptor{}).handle
+}
+
+func (i *interceptor) handle(
+	ctx xctx.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (rep interface{}, err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		Panics.Add(info.FullMethod, 1)
+		lg := csictx.GetLogger(ctx)
+		id, ok := csictx.GetRequestIDString(ctx)
+		if !ok {
+			id = newID()
+			lg = lg.With("requestID", id)
+		}
+		lg.Error("recovered from panic",
+			"method", info.FullMethod,
+			"panic", fmt.Sprint(r),
+			"stack", string(debug.Stack()))
+
+		rep = nil
+		err = status.Errorf(codes.Internal, "internal error: request ID %s", id)
+	}()
+	return handler(ctx, req)
+}
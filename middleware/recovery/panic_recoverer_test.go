@@ -0,0 +1,106 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package recovery
+
+import (
+	"bytes"
+	"context"
+	"expvar"
+	"log/slog"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	csictx "github.com/dell/gocsi/context"
+)
+
+const nodePublishMethod = "/csi.v1.Node/NodePublishVolume"
+
+func panicCount() int64 {
+	if v, ok := Panics.Get(nodePublishMethod).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestRecoverer(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := csictx.WithLogger(context.Background(),
+		slog.New(slog.NewTextHandler(buf, nil)))
+	ctx = metadata.NewIncomingContext(ctx,
+		metadata.Pairs(csictx.RequestIDKey, "req-1"))
+
+	info := &grpc.UnaryServerInfo{FullMethod: nodePublishMethod}
+	i := NewServerRecoverer()
+	n := panicCount()
+
+	rep, err := i(ctx, &csi.NodePublishVolumeRequest{}, info,
+		func(_ context.Context, _ interface{}) (interface{}, error) {
+			var req *csi.NodePublishVolumeRequest
+			return req.VolumeId, nil
+		})
+	assert.Nil(t, rep)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "req-1")
+	assert.Equal(t, n+1, panicCount())
+	assert.Contains(t, buf.String(), "recovered from panic")
+	assert.Contains(t, buf.String(), "nil pointer dereference")
+	assert.Contains(t, buf.String(), "panic_recoverer_test.go")
+}
+
+func TestRecovererNoRequestID(t *testing.T) {
+	orig := newID
+	defer func() { newID = orig }()
+	newID = func() string { return "generated" }
+
+	// A request ID is generated for the panic so the error may still be
+	// correlated with the log.
+	buf := &bytes.Buffer{}
+	ctx := csictx.WithLogger(context.Background(),
+		slog.New(slog.NewTextHandler(buf, nil)))
+	info := &grpc.UnaryServerInfo{FullMethod: nodePublishMethod}
+	_, err := NewServerRecoverer()(ctx,
+		&csi.NodePublishVolumeRequest{}, info,
+		func(_ context.Context, _ interface{}) (interface{}, error) {
+			panic("boom")
+		})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "internal error: request ID generated",
+		status.Convert(err).Message())
+	assert.Contains(t, buf.String(), "requestID=generated")
+}
+
+func TestRecovererNoPanic(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: nodePublishMethod}
+	n := panicCount()
+	rep, err := NewServerRecoverer()(context.Background(),
+		&csi.NodePublishVolumeRequest{}, info,
+		func(_ context.Context, _ interface{}) (interface{}, error) {
+			return &csi.NodePublishVolumeResponse{}, status.Error(
+				codes.NotFound, "not found")
+		})
+	assert.NotNil(t, rep)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, n, panicCount())
+}
@@ -19,11 +19,10 @@
 package requestid
 
 import (
-	"fmt"
 	"strconv"
-	"strings"
 	"sync/atomic"
 
+	"github.com/google/uuid"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
@@ -31,27 +30,95 @@ import (
 	csictx "github.com/dell/gocsi/context"
 )
 
+// newUUID returns a new UUID and may be replaced by tests.
+var newUUID = uuid.NewString
+
+// Option configures the interceptor.
+type Option func(*opts)
+
+type opts struct {
+	uuids bool
+}
+
+// WithUUIDs is an Option that generates request IDs that are UUIDs
+// instead of numbers from an atomic sequence counter. Please note that
+// csictx.GetRequestID does not return UUIDs, so handlers must use
+// csictx.GetRequestIDString instead.
+func WithUUIDs() Option {
+	return func(o *opts) {
+		o.uuids = true
+	}
+}
+
 type interceptor struct {
-	id uint64
+	opts opts
+	id   atomic.Uint64
 }
 
 // NewServerRequestIDInjector returns a new UnaryServerInterceptor
 // that reads a unique request ID from the incoming context's gRPC
 // metadata. If the incoming context does not contain gRPC metadata or
-// a request ID, then a new request ID is generated.
-func NewServerRequestIDInjector() grpc.UnaryServerInterceptor {
-	return newRequestIDInjector().handleServer
+// a request ID, then a new request ID is generated using an atomic
+// sequence counter, or with WithUUIDs, a UUID. Request IDs sent by
+// clients are opaque strings, ex. a UUID or a correlation ID provided by
+// a container orchestrator, of at most 128 bytes of printable ASCII; a
+// new request ID is generated in place of an invalid one. The request ID
+// is echoed back to the client in the response's trailer metadata.
+func NewServerRequestIDInjector(opts ...Option) grpc.UnaryServerInterceptor {
+	return newRequestIDInjector(opts...).handleServer
 }
 
 // NewClientRequestIDInjector provides a UnaryClientInterceptor
 // that injects the outgoing context with gRPC metadata that contains
-// a unique ID.
-func NewClientRequestIDInjector() grpc.UnaryClientInterceptor {
-	return newRequestIDInjector().handleClient
+// a unique ID. If the outgoing context does not have a request ID then
+// the ID of the incoming request, if any, is propagated. Otherwise a
+// new request ID is generated.
+func NewClientRequestIDInjector(opts ...Option) grpc.UnaryClientInterceptor {
+	return newRequestIDInjector(opts...).handleClient
 }
 
-func newRequestIDInjector() *interceptor {
-	return &interceptor{}
+func newRequestIDInjector(opts ...Option) *interceptor {
+	i := &interceptor{}
+	for _, setOpt := range opts {
+		setOpt(&i.opts)
+	}
+	return i
+}
+
+// newID returns a new request ID.
+func (s *interceptor) newID() string {
+	if s.opts.uuids {
+		return newUUID()
+	}
+	return strconv.FormatUint(s.id.Add(1), 10)
+}
+
+// maxIDLen is the maximum length of a request ID sent by a client.
+const maxIDLen = 128
+
+// getID returns the request ID from the provided gRPC metadata. A request
+// ID that is too long or contains characters other than printable ASCII
+// is ignored so that clients may not inject lines into the logs or send
+// oversized trailers.
+func getID(md metadata.MD) (string, bool) {
+	if szID := md.Get(csictx.RequestIDKey); len(szID) == 1 && validID(szID[0]) {
+		return szID[0], true
+	}
+	return "", false
+}
+
+// validID returns a flag indicating whether the provided request ID is
+// not empty, at most maxIDLen bytes long, and printable ASCII.
+func validID(id string) bool {
+	if id == "" || len(id) > maxIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
 }
 
 func (s *interceptor) handleServer(
@@ -60,45 +127,35 @@ func (s *interceptor) handleServer(
 	_ *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler,
 ) (interface{}, error) {
-	// storeID is a flag that indicates whether or not the request ID
-	// should be atomically stored in the interceptor's id field at
-	// the end of this function. If the ID was found in the incoming
-	// request and could be parsed successfully then the ID is stored.
-	// If the ID was generated server-side then the ID is not stored.
-	storeID := true
-
-	// Retrieve the gRPC metadata from the incoming context.
-	md, mdOK := metadata.FromIncomingContext(ctx)
-
-	// If no gRPC metadata was found then create some and ensure the
-	// context is a gRPC incoming context.
-	if !mdOK {
-		md = metadata.Pairs()
-		ctx = metadata.NewIncomingContext(ctx, md)
+	// Retrieve the gRPC metadata from the incoming context. If no gRPC
+	// metadata was found then create some.
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		md = metadata.MD{}
 	}
 
-	// Check the metadata from the request ID.
-	szID, szIDOK := md[csictx.RequestIDKey]
-
-	// If the metadata does not contain a request ID then create a new
-	// request ID and inject it into the metadata.
-	if !szIDOK || len(szID) != 1 {
-		szID = []string{fmt.Sprintf("%d", atomic.AddUint64(&s.id, 1))}
-		md.Append(csictx.RequestIDKey, strings.Join(szID, " "))
+	// Prefer the request ID sent by the client. If the metadata does
+	// not contain a valid request ID then create a new request ID and
+	// inject it into the metadata in place of the invalid one.
+	id, ok := getID(md)
+	if !ok {
+		id = s.newID()
+		md.Set(csictx.RequestIDKey, id)
 		ctx = metadata.NewIncomingContext(ctx, md)
-		storeID = false
+	} else if n, err := strconv.ParseUint(id, 10, 64); err == nil {
+		// A numeric request ID sent by the client becomes the value of
+		// the sequence counter so that the IDs generated for requests
+		// without one follow it.
+		s.id.Store(n)
 	}
 
-	// Parse the request ID from the
-	id, err := strconv.ParseUint(szID[0], 10, 64)
-	if err != nil {
-		id = atomic.AddUint64(&s.id, 1)
-		storeID = false
-	}
+	// Echo the request ID back to the client. An error is returned if
+	// the context is not associated with a gRPC server stream, ex. when
+	// the interceptor is invoked directly, and may be ignored.
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(csictx.RequestIDKey, id))
 
-	if storeID {
-		atomic.StoreUint64(&s.id, id)
-	}
+	// Add the request ID to the fields of the context's logger.
+	ctx = csictx.WithLogFields(ctx, "requestID", id)
 
 	return handler(ctx, req)
 }
@@ -112,16 +169,24 @@ func (s *interceptor) handleClient(
 	opts ...grpc.CallOption,
 ) error {
 	// Ensure there is an outgoing gRPC context with metadata.
-	md, mdOK := metadata.FromOutgoingContext(ctx)
-	if !mdOK {
-		md = metadata.Pairs()
-		ctx = metadata.NewOutgoingContext(ctx, md)
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		md = metadata.MD{}
 	}
 
-	// Ensure the request ID is set in the metadata.
-	if szID, szIDOK := md[csictx.RequestIDKey]; !szIDOK || len(szID) != 1 {
-		szID = []string{fmt.Sprintf("%d", atomic.AddUint64(&s.id, 1))}
-		md[csictx.RequestIDKey] = szID
+	// Ensure the request ID is set in the metadata. If the client is
+	// invoked on behalf of an incoming request then that request's ID
+	// is propagated so the calls may be correlated.
+	if _, ok := getID(md); !ok {
+		id, ok := "", false
+		if imd, imdOK := metadata.FromIncomingContext(ctx); imdOK {
+			id, ok = getID(imd)
+		}
+		if !ok {
+			id = s.newID()
+		}
+		md.Set(csictx.RequestIDKey, id)
+		ctx = metadata.NewOutgoingContext(ctx, md)
 	}
 
 	return invoker(ctx, method, req, rep, cc, opts...)
@@ -21,6 +21,7 @@ package requestid
 import (
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
 	csictx "github.com/dell/gocsi/context"
@@ -52,7 +53,6 @@ func TestNewRequestIDInjector(t *testing.T) {
 func TestInterceptorHandleServer(t *testing.T) {
 	tests := []struct {
 		name    string
-		id      uint64
 		getCtx  func() context.Context
 		req     interface{}
 		handler grpc.UnaryHandler
@@ -61,7 +61,6 @@ func TestInterceptorHandleServer(t *testing.T) {
 	}{
 		{
 			name:   "Basic positive",
-			id:     123,
 			getCtx: func() context.Context { return context.Background() },
 			req:    "test request",
 			handler: func(_ context.Context, _ interface{}) (interface{}, error) {
@@ -72,7 +71,6 @@ func TestInterceptorHandleServer(t *testing.T) {
 		},
 		{
 			name: "With good request ID",
-			id:   123,
 			getCtx: func() context.Context {
 				md := metadata.Pairs(
 					csictx.RequestIDKey, "2452",
@@ -88,7 +86,6 @@ func TestInterceptorHandleServer(t *testing.T) {
 		},
 		{
 			name: "Basic negative",
-			id:   1023,
 			getCtx: func() context.Context {
 				md := metadata.Pairs(
 					csictx.RequestIDKey, "non-uint-id",
@@ -105,9 +102,7 @@ func TestInterceptorHandleServer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := &interceptor{
-				id: tt.id,
-			}
+			s := &interceptor{}
 			got, err := s.handleServer(tt.getCtx(), tt.req, nil, tt.handler)
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -120,9 +115,6 @@ func TestInterceptorHandleServer(t *testing.T) {
 }
 
 func TestInterceptorHandleClient(t *testing.T) {
-	type fields struct {
-		id uint64
-	}
 	type args struct {
 		ctx     context.Context
 		method  string
@@ -134,15 +126,11 @@ func TestInterceptorHandleClient(t *testing.T) {
 	}
 	tests := []struct {
 		name    string
-		fields  fields
 		args    args
 		wantErr bool
 	}{
 		{
 			name: "Test case 1",
-			fields: fields{
-				id: 123,
-			},
 			args: args{
 				ctx:    context.Background(),
 				method: "exampleMethod",
@@ -159,12 +147,174 @@ func TestInterceptorHandleClient(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := &interceptor{
-				id: tt.fields.id,
-			}
+			s := &interceptor{}
 			if err := s.handleClient(tt.args.ctx, tt.args.method, tt.args.req, tt.args.rep, tt.args.cc, tt.args.invoker, tt.args.opts...); (err != nil) != tt.wantErr {
 				t.Errorf("interceptor.handleClient() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestHandleServerRequestID(t *testing.T) {
+	orig := newUUID
+	defer func() { newUUID = orig }()
+	newUUID = func() string { return "generated" }
+
+	tests := []struct {
+		name string
+		opts []Option
+		md   metadata.MD
+		want string
+	}{
+		{
+			name: "no metadata",
+			want: "1",
+		},
+		{
+			name: "no metadata with UUIDs",
+			opts: []Option{WithUUIDs()},
+			want: "generated",
+		},
+		{
+			name: "UUID request ID",
+			md: metadata.Pairs(
+				csictx.RequestIDKey, "9b2b6e0e-3a8c-4b53-9a43-5d1f0c6a7e21"),
+			want: "9b2b6e0e-3a8c-4b53-9a43-5d1f0c6a7e21",
+		},
+		{
+			name: "multiple request IDs",
+			md: metadata.Pairs(
+				csictx.RequestIDKey, "1", csictx.RequestIDKey, "2"),
+			want: "1",
+		},
+		{
+			name: "max length request ID",
+			md: metadata.Pairs(
+				csictx.RequestIDKey, strings.Repeat("a", 128)),
+			want: strings.Repeat("a", 128),
+		},
+		{
+			name: "request ID too long",
+			md: metadata.Pairs(
+				csictx.RequestIDKey, strings.Repeat("a", 129)),
+			want: "1",
+		},
+		{
+			name: "request ID with newline",
+			md: metadata.Pairs(
+				csictx.RequestIDKey, "id\nlevel=error msg=injected"),
+			want: "1",
+		},
+		{
+			name: "request ID with non-ASCII",
+			md:   metadata.Pairs(csictx.RequestIDKey, "id-\u00e9"),
+			want: "1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			var got string
+			_, err := newRequestIDInjector(tt.opts...).handleServer(ctx, nil, nil,
+				func(ctx context.Context, _ interface{}) (interface{}, error) {
+					got, _ = csictx.GetRequestIDString(ctx)
+					return nil, nil
+				})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHandleClientRequestID(t *testing.T) {
+	orig := newUUID
+	defer func() { newUUID = orig }()
+	newUUID = func() string { return "generated" }
+
+	tests := []struct {
+		name string
+		opts []Option
+		ctx  context.Context
+		want string
+	}{
+		{
+			name: "generated",
+			ctx:  context.Background(),
+			want: "1",
+		},
+		{
+			name: "generated with UUIDs",
+			opts: []Option{WithUUIDs()},
+			ctx:  context.Background(),
+			want: "generated",
+		},
+		{
+			name: "outgoing request ID",
+			ctx: metadata.NewOutgoingContext(context.Background(),
+				metadata.Pairs(csictx.RequestIDKey, "out")),
+			want: "out",
+		},
+		{
+			name: "propagated from incoming request",
+			ctx: metadata.NewIncomingContext(context.Background(),
+				metadata.Pairs(csictx.RequestIDKey, "in")),
+			want: "in",
+		},
+		{
+			name: "invalid incoming request ID",
+			ctx: metadata.NewIncomingContext(context.Background(),
+				metadata.Pairs(csictx.RequestIDKey, "in\r\n")),
+			want: "1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := newRequestIDInjector(tt.opts...).handleClient(
+				tt.ctx, "/csi.v1.Identity/Probe", nil, nil, nil,
+				func(ctx context.Context, _ string, _, _ interface{},
+					_ *grpc.ClientConn, _ ...grpc.CallOption,
+				) error {
+					md, _ := metadata.FromOutgoingContext(ctx)
+					got = md.Get(csictx.RequestIDKey)
+					return nil
+				})
+			assert.NoError(t, err)
+			assert.Equal(t, []string{tt.want}, got)
+		})
+	}
+}
+
+func TestHandleServerRequestIDSequence(t *testing.T) {
+	i := newRequestIDInjector()
+	handle := func(md metadata.MD) (uint64, bool) {
+		ctx := context.Background()
+		if md != nil {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		var (
+			id uint64
+			ok bool
+		)
+		_, err := i.handleServer(ctx, nil, nil,
+			func(ctx context.Context, _ interface{}) (interface{}, error) {
+				id, ok = csictx.GetRequestID(ctx)
+				return nil, nil
+			})
+		assert.NoError(t, err)
+		return id, ok
+	}
+
+	// The generated IDs are numbers that GetRequestID returns, and they
+	// follow the numeric IDs sent by clients.
+	id, ok := handle(nil)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), id)
+	id, _ = handle(metadata.Pairs(csictx.RequestIDKey, "41"))
+	assert.Equal(t, uint64(41), id)
+	id, _ = handle(nil)
+	assert.Equal(t, uint64(42), id)
+}
@@ -0,0 +1,252 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package secrets provides a server-side gRPC interceptor that resolves
+// references to secrets in the Secrets field of CSI requests.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	xctx "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	csictx "github.com/dell/gocsi/context"
+	"github.com/dell/gocsi/utils/rpcs"
+)
+
+// Resolver resolves references to secrets.
+type Resolver interface {
+	// Resolve returns the value of the secret referred to by ref, ex.
+	// "file:///etc/creds/password" or "env:PASSWORD".
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// ResolverFunc is an adapter that allows the use of an ordinary function
+// as a Resolver.
+type ResolverFunc func(ctx context.Context, ref string) (string, error)
+
+// Resolve returns f(ctx, ref).
+func (f ResolverFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+// NewFileResolver returns a Resolver for references with the "file"
+// scheme, ex. "file:///etc/creds/password". The value of the secret is
+// the content of the file without a trailing newline. Only files in the
+// provided directories may be referenced, after any symbolic links in
+// their paths are evaluated. If no directories are provided then no file
+// may be referenced.
+func NewFileResolver(dirs ...string) Resolver {
+	return ResolverFunc(func(_ context.Context, ref string) (string, error) {
+		u, err := url.Parse(ref)
+		if err != nil {
+			return "", err
+		}
+		if u.Host != "" && u.Host != "localhost" {
+			return "", fmt.Errorf("invalid file host: %s", u.Host)
+		}
+		path := filepath.Clean(u.Path)
+		if !filepath.IsAbs(path) {
+			return "", errors.New("file path is not absolute")
+		}
+		if path, err = filepath.EvalSymlinks(path); err != nil {
+			return "", err
+		}
+		if !inDirs(path, dirs) {
+			return "", errors.New("file is not in a permitted directory")
+		}
+		data, err := os.ReadFile(path) // #nosec G304
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	})
+}
+
+// inDirs returns a flag indicating whether the provided path is in one
+// of the provided directories.
+func inDirs(path string, dirs []string) bool {
+	for _, d := range dirs {
+		d, err := filepath.EvalSymlinks(d)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(d, path); err == nil &&
+			rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewEnvResolver returns a Resolver for references with the "env" scheme,
+// ex. "env:PASSWORD". The value of the secret is the value of the
+// environment variable, which is looked up with csictx.LookupEnv. Only the
+// provided environment variables may be referenced. A name that ends with
+// "*" permits the environment variables with the preceding prefix, ex.
+// "CSI_SECRET_*". If no names are provided then no environment variable
+// may be referenced.
+func NewEnvResolver(names ...string) Resolver {
+	return ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		name := strings.TrimPrefix(ref, "env:")
+		if !envPermitted(name, names) {
+			return "", fmt.Errorf(
+				"environment variable is not permitted: %s", name)
+		}
+		v, ok := csictx.LookupEnv(ctx, name)
+		if !ok {
+			return "", fmt.Errorf("environment variable not set: %s", name)
+		}
+		return v, nil
+	})
+}
+
+// envPermitted returns a flag indicating whether the provided environment
+// variable is one of, or has the prefix of one of, the provided names.
+func envPermitted(name string, names []string) bool {
+	if name == "" {
+		return false
+	}
+	for _, n := range names {
+		if pfx, ok := strings.CutSuffix(n, "*"); ok {
+			if strings.HasPrefix(name, pfx) {
+				return true
+			}
+		} else if name == n {
+			return true
+		}
+	}
+	return false
+}
+
+// Option configures the interceptor.
+type Option func(*opts)
+
+type opts struct {
+	resolvers map[string]Resolver
+}
+
+// WithResolver is an Option that sets the Resolver used for references
+// with the provided URL scheme, ex. "vault". A nil Resolver disables the
+// resolution of references with the scheme.
+func WithResolver(scheme string, r Resolver) Option {
+	return func(o *opts) {
+		o.resolvers[strings.ToLower(scheme)] = r
+	}
+}
+
+// NewServerSecretsResolver returns a new UnaryServerInterceptor that
+// replaces the values in a request's Secrets field that are references
+// to secrets with the values of the secrets. A value is a reference if
+// it begins with a URL scheme for which there is a Resolver, ex. "file:"
+// or "env:". Other values are not modified. A request whose secrets cannot
+// be resolved is rejected with codes.InvalidArgument.
+//
+// By default the "file" and "env" resolvers do not permit any files or
+// environment variables to be referenced. Use WithResolver with
+// NewFileResolver and NewEnvResolver to permit some.
+//
+// The secrets are resolved in a copy of the request that is passed to
+// the handler, so the resolved values are never seen by the interceptors
+// that precede this one in the chain, ex. the logging interceptor.
+func NewServerSecretsResolver(opts ...Option) grpc.UnaryServerInterceptor {
+	i := &interceptor{}
+	i.opts.resolvers = map[string]Resolver{
+		"file": NewFileResolver(),
+		"env":  NewEnvResolver(),
+	}
+
+	// Configure the interceptor's options.
+	for _, setOpt := range opts {
+		setOpt(&i.opts)
+	}
+
+	return i.handle
+}
+
+type interceptor struct {
+	opts opts
+}
+
+// schemeRX matches the scheme of a reference.
+var schemeRX = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*):`)
+
+func (i *interceptor) resolver(v string) (Resolver, bool) {
+	m := schemeRX.FindStringSubmatch(v)
+	if m == nil {
+		return nil, false
+	}
+	r, ok := i.opts.resolvers[strings.ToLower(m[1])]
+	return r, ok && r != nil
+}
+
+func (i *interceptor) handle(
+	ctx xctx.Context,
+	req interface{},
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	m, ok := rpcs.LookupRequest(req)
+	if !ok {
+		return handler(ctx, req)
+	}
+	secrets := m.Secrets(req)
+
+	// Determine whether any of the secrets are references before the
+	// request is copied.
+	var refs bool
+	for _, v := range secrets {
+		if _, refs = i.resolver(v); refs {
+			break
+		}
+	}
+	if !refs {
+		return handler(ctx, req)
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(secrets)) {
+		r, ok := i.resolver(secrets[k])
+		if !ok {
+			continue
+		}
+		s, err := r.Resolve(ctx, secrets[k])
+		if err != nil {
+			// Neither the reference nor the error is returned to the
+			// caller in case either contains sensitive information.
+			csictx.GetLogger(ctx).Warn("failed to resolve secret",
+				"key", k, "error", err)
+			return nil, status.Errorf(codes.InvalidArgument,
+				"failed to resolve secret: %s", k)
+		}
+		secrets[k] = s
+	}
+
+	return handler(ctx, m.WithSecrets(req, secrets))
+}
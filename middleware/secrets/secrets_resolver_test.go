@@ -0,0 +1,236 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dell/gocsi/middleware/internal/mwtest"
+)
+
+// capture returns a handler that records the request it receives.
+func capture(got *interface{}) grpc.UnaryHandler {
+	return func(_ context.Context, req interface{}) (interface{}, error) {
+		*got = req
+		return &csi.CreateVolumeResponse{}, nil
+	}
+}
+
+func TestResolveSecrets(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(path, []byte("s3cr3t\n"), 0o600))
+	t.Setenv("GOCSI_TEST_SECRET_USER", "admin")
+
+	vault := ResolverFunc(func(_ context.Context, ref string) (string, error) {
+		return strings.ToUpper(strings.TrimPrefix(ref, "vault:")), nil
+	})
+	i := NewServerSecretsResolver(
+		WithResolver("vault", vault),
+		WithResolver("file", NewFileResolver(dir)),
+		WithResolver("env", NewEnvResolver("GOCSI_TEST_SECRET_*")))
+
+	req := &csi.CreateVolumeRequest{
+		Name: "vol",
+		Secrets: map[string]string{
+			"password": "file://" + path,
+			"user":     "env:GOCSI_TEST_SECRET_USER",
+			"token":    "vault:token",
+			"literal":  "plain-value",
+		},
+	}
+
+	var got interface{}
+	_, err := i(context.Background(), req, mwtest.CreateVolumeInfo, capture(&got))
+	require.NoError(t, err)
+
+	greq, ok := got.(*csi.CreateVolumeRequest)
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{
+		"password": "s3cr3t",
+		"user":     "admin",
+		"token":    "TOKEN",
+		"literal":  "plain-value",
+	}, greq.Secrets)
+	assert.Equal(t, "vol", greq.Name)
+
+	// The original request is not modified.
+	assert.Equal(t, "file://"+path, req.Secrets["password"])
+	assert.Equal(t, "env:GOCSI_TEST_SECRET_USER", req.Secrets["user"])
+}
+
+func TestResolveSecretsDenyByDefault(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(path, []byte("s3cr3t"), 0o600))
+	t.Setenv("GOCSI_TEST_SECRET_USER", "admin")
+
+	// Neither files nor environment variables may be referenced unless
+	// they are permitted explicitly.
+	i := NewServerSecretsResolver()
+	for _, ref := range []string{
+		"file://" + path,
+		"file:///etc/passwd",
+		"env:GOCSI_TEST_SECRET_USER",
+		"env:PATH",
+	} {
+		req := &csi.CreateVolumeRequest{
+			Secrets: map[string]string{"password": ref},
+		}
+		var got interface{}
+		_, err := i(context.Background(), req, mwtest.CreateVolumeInfo, capture(&got))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), ref)
+		assert.Nil(t, got)
+	}
+}
+
+func TestResolveSecretsUnchanged(t *testing.T) {
+	i := NewServerSecretsResolver()
+
+	// Requests without references are passed to the handler as is.
+	for _, req := range []interface{}{
+		&csi.NodeStageVolumeRequest{
+			Secrets: map[string]string{"password": "plain"},
+		},
+		&csi.NodeStageVolumeRequest{},
+		&csi.ProbeRequest{},
+	} {
+		var got interface{}
+		_, err := i(context.Background(), req, mwtest.CreateVolumeInfo, capture(&got))
+		assert.NoError(t, err)
+		assert.Same(t, req, got)
+	}
+}
+
+func TestResolveSecretsError(t *testing.T) {
+	i := NewServerSecretsResolver(WithResolver("vault",
+		ResolverFunc(func(context.Context, string) (string, error) {
+			return "", errors.New("vault unavailable")
+		})))
+
+	for _, ref := range []string{
+		"env:GOCSI_TEST_SECRET_MISSING",
+		"file:///gocsi/test/missing",
+		"file:relative",
+		"vault:token",
+	} {
+		req := &csi.DeleteVolumeRequest{
+			Secrets: map[string]string{"password": ref},
+		}
+		var got interface{}
+		_, err := i(context.Background(), req, mwtest.CreateVolumeInfo, capture(&got))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), ref)
+		assert.NotContains(t, status.Convert(err).Message(), ref)
+		assert.Nil(t, got)
+	}
+}
+
+func TestDisableResolver(t *testing.T) {
+	t.Setenv("GOCSI_TEST_SECRET_USER", "admin")
+	i := NewServerSecretsResolver(WithResolver("env", nil))
+
+	req := &csi.CreateVolumeRequest{
+		Secrets: map[string]string{"user": "env:GOCSI_TEST_SECRET_USER"},
+	}
+	var got interface{}
+	_, err := i(context.Background(), req, mwtest.CreateVolumeInfo, capture(&got))
+	assert.NoError(t, err)
+	assert.Same(t, req, got)
+}
+
+func TestFileResolverDirs(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(path, []byte("s3cr3t"), 0o600))
+
+	ctx := context.Background()
+	v, err := NewFileResolver(dir).Resolve(ctx, "file://"+path)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", v)
+
+	_, err = NewFileResolver(filepath.Join(dir, "other")).Resolve(
+		ctx, "file://"+path)
+	assert.Error(t, err)
+
+	_, err = NewFileResolver(filepath.Join(dir, "other")).Resolve(
+		ctx, "file://"+filepath.Join(dir, "other", "..", "password"))
+	assert.Error(t, err)
+
+	_, err = NewFileResolver().Resolve(ctx, "file://remote"+path)
+	assert.Error(t, err)
+
+	// No file may be referenced without a permitted directory.
+	_, err = NewFileResolver().Resolve(ctx, "file://"+path)
+	assert.ErrorContains(t, err, "not in a permitted directory")
+
+	// A symbolic link in a permitted directory may not refer to a file
+	// outside of it.
+	other := t.TempDir()
+	outside := filepath.Join(other, "token")
+	require.NoError(t, os.WriteFile(outside, []byte("token"), 0o600))
+	link := filepath.Join(dir, "link")
+	require.NoError(t, os.Symlink(outside, link))
+	_, err = NewFileResolver(dir).Resolve(ctx, "file://"+link)
+	assert.ErrorContains(t, err, "not in a permitted directory")
+
+	// A permitted directory may be referred to by a symbolic link.
+	dirLink := filepath.Join(other, "dir")
+	require.NoError(t, os.Symlink(dir, dirLink))
+	v, err = NewFileResolver(dirLink).Resolve(ctx, "file://"+path)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", v)
+}
+
+func TestEnvResolverNames(t *testing.T) {
+	t.Setenv("GOCSI_TEST_SECRET_USER", "admin")
+	t.Setenv("GOCSI_TEST_PASSWORD", "s3cr3t")
+	ctx := context.Background()
+
+	r := NewEnvResolver("GOCSI_TEST_SECRET_*", "GOCSI_TEST_PASSWORD")
+	v, err := r.Resolve(ctx, "env:GOCSI_TEST_SECRET_USER")
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", v)
+	v, err = r.Resolve(ctx, "env:GOCSI_TEST_PASSWORD")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", v)
+
+	for _, ref := range []string{
+		"env:PATH",
+		"env:GOCSI_TEST_PASSWORD_OLD",
+		"env:",
+	} {
+		_, err = r.Resolve(ctx, ref)
+		assert.ErrorContains(t, err, "not permitted", ref)
+	}
+
+	_, err = NewEnvResolver().Resolve(ctx, "env:GOCSI_TEST_PASSWORD")
+	assert.ErrorContains(t, err, "not permitted")
+}
@@ -21,13 +21,13 @@ package etcd
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 	"path"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/akutz/gosync"
-	log "github.com/sirupsen/logrus"
 	etcd "go.etcd.io/etcd/client/v3"
 	etcdsync "go.etcd.io/etcd/client/v3/concurrency"
 
@@ -65,7 +65,12 @@ func New(
 		config = &cfg
 	}
 
-	log.WithFields(fields).Info("creating serial vol etcd lock provider")
+	args := make([]any, 0, len(fields)*2)
+	for k, v := range fields {
+		args = append(args, k, v)
+	}
+	csictx.GetLogger(ctx).Info(
+		"creating serial vol etcd lock provider", args...)
 
 	client, err := etcd.New(*config)
 	if err != nil {
@@ -79,6 +84,14 @@ func New(
 	}, nil
 }
 
+// NewConfig returns a new etcd client configuration initialized from
+// the environment variables defined in this package. The configuration
+// may be used by other components to connect to the same etcd cluster
+// as the serial volume access lock provider.
+func NewConfig(ctx context.Context) (etcd.Config, error) {
+	return initConfig(ctx, map[string]interface{}{})
+}
+
 func initConfig(
 	ctx context.Context,
 	fields map[string]interface{},
@@ -210,7 +223,7 @@ func (p *provider) GetLockWithName(
 func (p *provider) getLock(
 	ctx context.Context, pfx string,
 ) (gosync.TryLocker, error) {
-	log.Debugf("EtcdVolumeLockProvider: getLock: pfx=%v", pfx)
+	csictx.GetLogger(ctx).Debug("EtcdVolumeLockProvider: getLock", "pfx", pfx)
 
 	opts := []etcdsync.SessionOption{etcdsync.WithContext(ctx)}
 	if p.ttl > 0 {
@@ -255,9 +268,10 @@ func (m *TryMutex) Lock() {
 		ctx = m.ctx
 	}
 	if err := m.mtx.Lock(ctx); err != nil {
-		log.Debugf("TryMutex: lock err: %v", err)
+		csictx.GetLogger(ctx).Debug("TryMutex: lock err", "error", err)
 		if err != context.Canceled && err != context.DeadlineExceeded {
-			log.Panicf("TryMutex: lock panic: %v", err)
+			csictx.GetLogger(ctx).Error("TryMutex: lock panic", "error", err)
+			panic(fmt.Sprintf("TryMutex: lock panic: %v", err))
 		}
 	}
 }
@@ -275,9 +289,10 @@ func (m *TryMutex) Unlock() {
 		ctx = m.ctx
 	}
 	if err := m.mtx.Unlock(ctx); err != nil {
-		log.Debugf("TryMutex: unlock err: %v", err)
+		csictx.GetLogger(ctx).Debug("TryMutex: unlock err", "error", err)
 		if err != context.Canceled && err != context.DeadlineExceeded {
-			log.Panicf("TryMutex: unlock panic: %v", err)
+			csictx.GetLogger(ctx).Error("TryMutex: unlock panic", "error", err)
+			panic(fmt.Sprintf("TryMutex: unlock panic: %v", err))
 		}
 	}
 }
@@ -286,7 +301,7 @@ func (m *TryMutex) Unlock() {
 func (m *TryMutex) Close() error {
 	// log.Debug("TryMutex: close")
 	if err := m.sess.Close(); err != nil {
-		log.Errorf("TryMutex: close err: %v", err)
+		csictx.GetLogger(m.ctx).Error("TryMutex: close err", "error", err)
 		return err
 	}
 	return nil
@@ -308,9 +323,10 @@ func (m *TryMutex) TryLock(timeout time.Duration) bool {
 	}
 
 	if err := m.mtx.Lock(ctx); err != nil {
-		log.Debugf("TryMutex: TryLock err: %v", err)
+		csictx.GetLogger(ctx).Debug("TryMutex: TryLock err", "error", err)
 		if err != context.Canceled && err != context.DeadlineExceeded {
-			log.Panicf("TryMutex: TryLock panic: %v", err)
+			csictx.GetLogger(ctx).Error("TryMutex: TryLock panic", "error", err)
+			panic(fmt.Sprintf("TryMutex: TryLock panic: %v", err))
 		}
 		return false
 	}
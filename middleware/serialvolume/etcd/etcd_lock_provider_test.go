@@ -19,6 +19,7 @@
 package etcd
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
@@ -28,6 +29,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"io"
+	"log/slog"
 	"math/big"
 	"net/url"
 	"os"
@@ -37,6 +39,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	csictx "github.com/dell/gocsi/context"
 	mwtypes "github.com/dell/gocsi/middleware/serialvolume/lockprovider"
 	"github.com/stretchr/testify/assert"
 	"go.etcd.io/etcd/client/pkg/v3/transport"
@@ -171,6 +174,34 @@ func TestTryMutex_Lock(t *testing.T) {
 	}
 }
 
+func TestTryMutex_LockCtxLogger(t *testing.T) {
+	ctx := context.Background()
+
+	m1, err := p.GetLockWithID(ctx, t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m1.(io.Closer).Close()
+	defer m1.Unlock()
+	m1.Lock()
+
+	m2, err := p.GetLockWithID(ctx, t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m2.(io.Closer).Close()
+
+	// The failure to lock m2 is logged with the logger of the context
+	// used with Lock.
+	buf := &bytes.Buffer{}
+	lockCtx, cancel := context.WithCancel(csictx.WithLogger(ctx, slog.New(
+		slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+	cancel()
+	m2.(*TryMutex).LockCtx = lockCtx
+	m2.Lock()
+	assert.Contains(t, buf.String(), "TryMutex: lock err")
+}
+
 func ExampleTryMutex_TryLock() {
 	const lockName = "ExampleTryMutex_TryLock"
 
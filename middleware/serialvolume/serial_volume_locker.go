@@ -24,13 +24,14 @@ import (
 	"time"
 
 	"github.com/akutz/gosync"
-	"github.com/container-storage-interface/spec/lib/go/csi"
 	xctx "golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	csictx "github.com/dell/gocsi/context"
 	mwtypes "github.com/dell/gocsi/middleware/serialvolume/lockprovider"
+	"github.com/dell/gocsi/utils/rpcs"
 )
 
 const pending = "pending"
@@ -68,6 +69,9 @@ func WithLockProvider(p mwtypes.VolumeLockerProvider) Option {
 //   - ControllerUnpublishVolume
 //   - NodePublishVolume
 //   - NodeUnpublishVolume
+//
+// CreateVolume is serialized by the volume's name and the other RPCs by
+// the volume's ID. The RPCs are those whose rpcs.Method is Serialized.
 func New(opts ...Option) grpc.UnaryServerInterceptor {
 	i := &interceptor{}
 
@@ -95,139 +99,45 @@ type interceptor struct {
 func (i *interceptor) handle(
 	ctx xctx.Context,
 	req interface{},
-	info *grpc.UnaryServerInfo,
-	handler grpc.UnaryHandler,
-) (interface{}, error) {
-	switch treq := req.(type) {
-	case *csi.ControllerPublishVolumeRequest:
-		return i.controllerPublishVolume(ctx, treq, info, handler)
-	case *csi.ControllerUnpublishVolumeRequest:
-		return i.controllerUnpublishVolume(ctx, treq, info, handler)
-	case *csi.CreateVolumeRequest:
-		return i.createVolume(ctx, treq, info, handler)
-	case *csi.DeleteVolumeRequest:
-		return i.deleteVolume(ctx, treq, info, handler)
-	case *csi.NodePublishVolumeRequest:
-		return i.nodePublishVolume(ctx, treq, info, handler)
-	case *csi.NodeUnpublishVolumeRequest:
-		return i.nodeUnpublishVolume(ctx, treq, info, handler)
-	}
-
-	return handler(ctx, req)
-}
-
-func (i *interceptor) controllerPublishVolume(
-	ctx context.Context,
-	req *csi.ControllerPublishVolumeRequest,
-	_ *grpc.UnaryServerInfo,
-	handler grpc.UnaryHandler,
-) (res interface{}, resErr error) {
-	lock, err := i.opts.locker.GetLockWithID(ctx, req.VolumeId)
-	if err != nil {
-		return nil, err
-	}
-	if closer, ok := lock.(io.Closer); ok {
-		defer closer.Close()
-	}
-	if !lock.TryLock(i.opts.timeout) {
-		return nil, status.Error(codes.Aborted, pending)
-	}
-	defer lock.Unlock()
-
-	return handler(ctx, req)
-}
-
-func (i *interceptor) controllerUnpublishVolume(
-	ctx context.Context,
-	req *csi.ControllerUnpublishVolumeRequest,
 	_ *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler,
-) (res interface{}, resErr error) {
-	lock, err := i.opts.locker.GetLockWithID(ctx, req.VolumeId)
-	if err != nil {
-		return nil, err
-	}
-	if closer, ok := lock.(io.Closer); ok {
-		defer closer.Close()
-	}
-	if !lock.TryLock(i.opts.timeout) {
-		return nil, status.Error(codes.Aborted, pending)
-	}
-	defer lock.Unlock()
-
-	return handler(ctx, req)
-}
-
-func (i *interceptor) createVolume(
-	ctx context.Context,
-	req *csi.CreateVolumeRequest,
-	_ *grpc.UnaryServerInfo,
-	handler grpc.UnaryHandler,
-) (res interface{}, resErr error) {
-	lock, err := i.opts.locker.GetLockWithName(ctx, req.Name)
-	if err != nil {
-		return nil, err
+) (interface{}, error) {
+	m, ok := rpcs.LookupRequest(req)
+	if !ok || !m.Serialized {
+		return handler(ctx, req)
 	}
-	if closer, ok := lock.(io.Closer); ok {
-		defer closer.Close()
+	if name, ok := m.ObjectName(req); ok && m.Creates == "volume" {
+		return i.serialize(ctx, req, handler, csictx.Lock{
+			Type: csictx.LockTypeVolumeName, Name: name,
+		})
 	}
-	if !lock.TryLock(i.opts.timeout) {
-		return nil, status.Error(codes.Aborted, pending)
+	if id, ok := m.VolumeID(req); ok {
+		return i.serialize(ctx, req, handler, csictx.Lock{
+			Type: csictx.LockTypeVolumeID, Name: id,
+		})
 	}
-	defer lock.Unlock()
 
 	return handler(ctx, req)
 }
 
-func (i *interceptor) deleteVolume(
+// serialize invokes the handler while holding the provided lock. An
+// Aborted error is returned if the lock is not acquired before the
+// interceptor's timeout.
+func (i *interceptor) serialize(
 	ctx context.Context,
-	req *csi.DeleteVolumeRequest,
-	_ *grpc.UnaryServerInfo,
-	handler grpc.UnaryHandler,
-) (res interface{}, resErr error) {
-	lock, err := i.opts.locker.GetLockWithID(ctx, req.VolumeId)
-	if err != nil {
-		return nil, err
-	}
-	if closer, ok := lock.(io.Closer); ok {
-		defer closer.Close()
-	}
-	if !lock.TryLock(i.opts.timeout) {
-		return nil, status.Error(codes.Aborted, pending)
-	}
-	defer lock.Unlock()
-
-	return handler(ctx, req)
-}
-
-func (i *interceptor) nodePublishVolume(
-	ctx context.Context,
-	req *csi.NodePublishVolumeRequest,
-	_ *grpc.UnaryServerInfo,
+	req interface{},
 	handler grpc.UnaryHandler,
+	held csictx.Lock,
 ) (res interface{}, resErr error) {
-	lock, err := i.opts.locker.GetLockWithID(ctx, req.VolumeId)
-	if err != nil {
-		return nil, err
-	}
-	if closer, ok := lock.(io.Closer); ok {
-		defer closer.Close()
-	}
-	if !lock.TryLock(i.opts.timeout) {
-		return nil, status.Error(codes.Aborted, pending)
+	var (
+		lock gosync.TryLocker
+		err  error
+	)
+	if held.Type == csictx.LockTypeVolumeName {
+		lock, err = i.opts.locker.GetLockWithName(ctx, held.Name)
+	} else {
+		lock, err = i.opts.locker.GetLockWithID(ctx, held.Name)
 	}
-	defer lock.Unlock()
-
-	return handler(ctx, req)
-}
-
-func (i *interceptor) nodeUnpublishVolume(
-	ctx context.Context,
-	req *csi.NodeUnpublishVolumeRequest,
-	_ *grpc.UnaryServerInfo,
-	handler grpc.UnaryHandler,
-) (res interface{}, resErr error) {
-	lock, err := i.opts.locker.GetLockWithID(ctx, req.VolumeId)
 	if err != nil {
 		return nil, err
 	}
@@ -238,6 +148,7 @@ func (i *interceptor) nodeUnpublishVolume(
 		return nil, status.Error(codes.Aborted, pending)
 	}
 	defer lock.Unlock()
+	ctx = csictx.WithHeldLock(ctx, held)
 
 	return handler(ctx, req)
 }
@@ -28,6 +28,8 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+
+	csictx "github.com/dell/gocsi/context"
 )
 
 func TestCreateVolume(t *testing.T) {
@@ -319,3 +321,77 @@ func (m *MockLock) Unlock() {
 func (m *MockLock) Close() error {
 	return nil
 }
+
+func TestHeldLocks(t *testing.T) {
+	interceptor := New(WithTimeout(1 * time.Second))
+
+	tests := []struct {
+		name string
+		req  interface{}
+		want csictx.Lock
+	}{
+		{
+			name: "volume name",
+			req:  &csi.CreateVolumeRequest{Name: "test-volume"},
+			want: csictx.Lock{
+				Type: csictx.LockTypeVolumeName, Name: "test-volume",
+			},
+		},
+		{
+			name: "volume ID",
+			req:  &csi.NodePublishVolumeRequest{VolumeId: "test-volume-id"},
+			want: csictx.Lock{
+				Type: csictx.LockTypeVolumeID, Name: "test-volume-id",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var locks []csictx.Lock
+			handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+				info, _ := csictx.GetRequestInfo(ctx)
+				locks = info.Locks
+				return nil, nil
+			}
+			_, err := interceptor(context.Background(), tt.req,
+				&grpc.UnaryServerInfo{}, handler)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(locks) != 1 || locks[0] != tt.want {
+				t.Fatalf("expected locks %v, got %v", tt.want, locks)
+			}
+		})
+	}
+}
+
+func TestNoLocks(t *testing.T) {
+	interceptor := New(WithTimeout(1 * time.Second))
+
+	for _, req := range []interface{}{
+		&csi.NodeGetVolumeStatsRequest{VolumeId: "test-volume-id"},
+		&csi.ControllerGetVolumeRequest{VolumeId: "test-volume-id"},
+		&csi.CreateSnapshotRequest{
+			Name: "test-snapshot", SourceVolumeId: "test-volume-id",
+		},
+		&csi.NodeStageVolumeRequest{VolumeId: "test-volume-id"},
+		&csi.NodeUnstageVolumeRequest{VolumeId: "test-volume-id"},
+		&csi.ControllerExpandVolumeRequest{VolumeId: "test-volume-id"},
+		&csi.NodeExpandVolumeRequest{VolumeId: "test-volume-id"},
+	} {
+		var locks []csictx.Lock
+		handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+			info, _ := csictx.GetRequestInfo(ctx)
+			locks = info.Locks
+			return nil, nil
+		}
+		_, err := interceptor(context.Background(), req,
+			&grpc.UnaryServerInfo{}, handler)
+		if err != nil {
+			t.Fatalf("%T: expected no error, got %v", req, err)
+		}
+		if len(locks) != 0 {
+			t.Fatalf("%T: expected no locks, got %v", req, locks)
+		}
+	}
+}
@@ -19,13 +19,13 @@
 package specvalidator
 
 import (
+	"expvar"
 	"os"
 	"reflect"
 	"regexp"
 	"strconv"
 	"sync"
 
-	log "github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -34,7 +34,9 @@ import (
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 
+	csictx "github.com/dell/gocsi/context"
 	"github.com/dell/gocsi/utils/middleware"
+	"github.com/dell/gocsi/utils/rpcs"
 )
 
 // Option configures the spec validator interceptor.
@@ -42,18 +44,27 @@ type Option func(*opts)
 
 type opts struct {
 	sync.Mutex
-	reqValidation               bool
-	repValidation               bool
-	requiresStagingTargetPath   bool
-	requiresVolContext          bool
-	requiresPubContext          bool
-	requiresCtlrNewVolSecrets   bool
-	requiresCtlrDelVolSecrets   bool
-	requiresCtlrPubVolSecrets   bool
-	requiresCtlrUnpubVolSecrets bool
-	requiresNodeStgVolSecrets   bool
-	requiresNodePubVolSecrets   bool
-	disableFieldLenCheck        bool
+	reqValidation             bool
+	repValidation             bool
+	reqWarnOnly               bool
+	repWarnOnly               bool
+	requiresStagingTargetPath bool
+	requiresVolContext        bool
+	requiresPubContext        bool
+	requiresSecrets           map[string]bool
+	disableFieldLenCheck      bool
+	fieldLimits               map[string]int
+}
+
+// withRequiresSecrets returns an Option that indicates the requests of the
+// RPC with the provided method name must contain non-empty secrets data.
+func withRequiresSecrets(method string) Option {
+	return func(o *opts) {
+		if o.requiresSecrets == nil {
+			o.requiresSecrets = map[string]bool{}
+		}
+		o.requiresSecrets[method] = true
+	}
 }
 
 // WithRequestValidation is a Option that enables request validation.
@@ -70,6 +81,24 @@ func WithResponseValidation() Option {
 	}
 }
 
+// WithRequestWarnOnly is an Option that puts request validation into
+// audit mode. Invalid requests are logged and counted in Violations,
+// but are still passed to the next handler in the chain.
+func WithRequestWarnOnly() Option {
+	return func(o *opts) {
+		o.reqWarnOnly = true
+	}
+}
+
+// WithResponseWarnOnly is an Option that puts response validation into
+// audit mode. Invalid responses are logged and counted in Violations,
+// but are still returned to the caller unchanged.
+func WithResponseWarnOnly() Option {
+	return func(o *opts) {
+		o.repWarnOnly = true
+	}
+}
+
 // WithRequiresStagingTargetPath is a Option that indicates
 // NodePublishVolume requests must have non-empty StagingTargetPath
 // fields.
@@ -102,54 +131,42 @@ func WithRequiresPublishContext() Option {
 // that indicates the eponymous requests must contain non-empty secrets
 // data.
 func WithRequiresControllerCreateVolumeSecrets() Option {
-	return func(o *opts) {
-		o.requiresCtlrNewVolSecrets = true
-	}
+	return withRequiresSecrets("CreateVolume")
 }
 
 // WithRequiresControllerDeleteVolumeSecrets is a Option
 // that indicates the eponymous requests must contain non-empty credentials
 // data.
 func WithRequiresControllerDeleteVolumeSecrets() Option {
-	return func(o *opts) {
-		o.requiresCtlrDelVolSecrets = true
-	}
+	return withRequiresSecrets("DeleteVolume")
 }
 
 // WithRequiresControllerPublishVolumeSecrets is a Option
 // that indicates the eponymous requests must contain non-empty credentials
 // data.
 func WithRequiresControllerPublishVolumeSecrets() Option {
-	return func(o *opts) {
-		o.requiresCtlrPubVolSecrets = true
-	}
+	return withRequiresSecrets("ControllerPublishVolume")
 }
 
 // WithRequiresControllerUnpublishVolumeSecrets is a Option
 // that indicates the eponymous requests must contain non-empty credentials
 // data.
 func WithRequiresControllerUnpublishVolumeSecrets() Option {
-	return func(o *opts) {
-		o.requiresCtlrUnpubVolSecrets = true
-	}
+	return withRequiresSecrets("ControllerUnpublishVolume")
 }
 
 // WithRequiresNodeStageVolumeSecrets is a Option
 // that indicates the eponymous requests must contain non-empty credentials
 // data.
 func WithRequiresNodeStageVolumeSecrets() Option {
-	return func(o *opts) {
-		o.requiresNodeStgVolSecrets = true
-	}
+	return withRequiresSecrets("NodeStageVolume")
 }
 
 // WithRequiresNodePublishVolumeSecrets is a Option
 // that indicates the eponymous requests must contain non-empty credentials
 // data.
 func WithRequiresNodePublishVolumeSecrets() Option {
-	return func(o *opts) {
-		o.requiresNodePubVolSecrets = true
-	}
+	return withRequiresSecrets("NodePublishVolume")
 }
 
 // WithDisableFieldLenCheck is a Option
@@ -160,6 +177,28 @@ func WithDisableFieldLenCheck() Option {
 	}
 }
 
+// Violations records the number of spec violations detected by all spec
+// validator interceptors in the process, whether enforced or not. The map
+// is keyed by "request:" or "response:" followed by the full gRPC method
+// name, and is published via expvar as "gocsi.specvalidator.violations".
+var Violations = expvar.NewMap("gocsi.specvalidator.violations")
+
+// WithFieldLimit is an Option that overrides the maximum permitted size
+// of the named message field when field lengths are validated. The name
+// is the Go field name of the CSI message, ex. VolumeId. For string fields
+// the limit applies to the length of the string. For map fields, such as
+// VolumeContext, the limit applies to the combined length of all keys and
+// values; the limit for each individual key and value may be set by
+// appending "[]" to the field name, ex. VolumeContext[].
+func WithFieldLimit(name string, limit int) Option {
+	return func(o *opts) {
+		if o.fieldLimits == nil {
+			o.fieldLimits = map[string]int{}
+		}
+		o.fieldLimits[name] = limit
+	}
+}
+
 type interceptor struct {
 	opts opts
 }
@@ -228,7 +267,16 @@ func (s *interceptor) handle(
 	if s.opts.reqValidation {
 		// Validate the request against the CSI specification.
 		if err := s.validateRequest(ctx, method, req); err != nil {
-			return nil, err
+			Violations.Add("request:"+method, 1)
+
+			// In audit mode the violation is only logged and the
+			// request proceeds as if it were valid.
+			if !s.opts.reqWarnOnly {
+				return nil, err
+			}
+			csictx.GetLogger(ctx).Warn("invalid request; passing through",
+				"method", method,
+				"valErr", err)
 		}
 	}
 
@@ -241,9 +289,19 @@ func (s *interceptor) handle(
 	}
 
 	if s.opts.repValidation {
-		log.Debug("response validation enabled")
+		csictx.GetLogger(ctx).Debug("response validation enabled")
 		// Validate the response against the CSI specification.
 		if err := s.validateResponse(ctx, method, rep); err != nil {
+			Violations.Add("response:"+method, 1)
+
+			// In audit mode the violation is only logged and the
+			// original response is returned to the caller.
+			if s.opts.repWarnOnly {
+				csictx.GetLogger(ctx).Warn("invalid response; passing through",
+					"method", method,
+					"valErr", err)
+				return rep, nil
+			}
 
 			// If an error occurred while validating the response, it is
 			// imperative the response not be discarded as it could be
@@ -261,11 +319,10 @@ func (s *interceptor) handle(
 			// the encoding error, validation error, and return the
 			// original response.
 			if err2 != nil {
-				log.WithFields(map[string]interface{}{
-					"encErr": err2,
-					"valErr": err,
-				}).Error("failed to encode error details; " +
-					"returning invalid response")
+				csictx.GetLogger(ctx).Error("failed to encode error details; "+
+					"returning invalid response",
+					"encErr", err2,
+					"valErr", err)
 
 				return rep, nil
 			}
@@ -279,13 +336,6 @@ func (s *interceptor) handle(
 	return rep, err
 }
 
-type interceptorHasVolumeID interface {
-	GetVolumeId() string
-}
-type interceptorHasUserCredentials interface {
-	GetUserCredentials() map[string]string
-}
-
 type interceptorHasVolumeContext interface {
 	GetVolumeContext() map[string]string
 }
@@ -294,6 +344,60 @@ type interceptorHasPublishContext interface {
 	GetPublishContext() map[string]string
 }
 
+// validateFunc validates a CSI message beyond the checks common to all
+// messages.
+type validateFunc func(*interceptor, context.Context, interface{}) error
+
+// validator returns a validateFunc for the messages of type *T.
+func validator[T any](
+	fn func(*interceptor, context.Context, T) error,
+) validateFunc {
+	return func(s *interceptor, ctx context.Context, msg interface{}) error {
+		tmsg, ok := msg.(*T)
+		if !ok {
+			return nil
+		}
+		return fn(s, ctx, *tmsg)
+	}
+}
+
+// requestValidators are the validators of the requests of the RPCs with
+// the eponymous method names. RPCs whose requests require no validation
+// beyond the checks common to all requests are omitted.
+var requestValidators = map[string]validateFunc{
+	// Controller Service
+	"CreateVolume":               validator((*interceptor).validateCreateVolumeRequest),
+	"DeleteVolume":               validator((*interceptor).validateDeleteVolumeRequest),
+	"ControllerPublishVolume":    validator((*interceptor).validateControllerPublishVolumeRequest),
+	"ControllerUnpublishVolume":  validator((*interceptor).validateControllerUnpublishVolumeRequest),
+	"ValidateVolumeCapabilities": validator((*interceptor).validateValidateVolumeCapabilitiesRequest),
+	"GetCapacity":                validator((*interceptor).validateGetCapacityRequest),
+
+	// Node Service
+	"NodeStageVolume":     validator((*interceptor).validateNodeStageVolumeRequest),
+	"NodeUnstageVolume":   validator((*interceptor).validateNodeUnstageVolumeRequest),
+	"NodePublishVolume":   validator((*interceptor).validateNodePublishVolumeRequest),
+	"NodeUnpublishVolume": validator((*interceptor).validateNodeUnpublishVolumeRequest),
+}
+
+// responseValidators are the validators of the responses of the RPCs with
+// the eponymous method names. RPCs whose responses require no validation
+// beyond the checks common to all responses are omitted.
+var responseValidators = map[string]validateFunc{
+	// Controller Service
+	"CreateVolume":              validator((*interceptor).validateCreateVolumeResponse),
+	"ControllerPublishVolume":   validator((*interceptor).validateControllerPublishVolumeResponse),
+	"ListVolumes":               validator((*interceptor).validateListVolumesResponse),
+	"ControllerGetCapabilities": validator((*interceptor).validateControllerGetCapabilitiesResponse),
+
+	// Identity Service
+	"GetPluginInfo": validator((*interceptor).validateGetPluginInfoResponse),
+
+	// Node Service
+	"NodeGetInfo":         validator((*interceptor).validateNodeGetInfoResponse),
+	"NodeGetCapabilities": validator((*interceptor).validateNodeGetCapabilitiesResponse),
+}
+
 func (s *interceptor) validateRequest(
 	ctx context.Context,
 	_ string,
@@ -305,18 +409,23 @@ func (s *interceptor) validateRequest(
 
 	// Validate field sizes.
 	if !s.opts.disableFieldLenCheck {
-		if err := validateFieldSizes(req); err != nil {
+		if err := validateFieldSizes(ctx, req, s.opts.fieldLimits); err != nil {
 			return err
 		}
 	}
 
+	// The request is described by the RPC registry. Requests that are not
+	// CSI requests are not validated further.
+	m, ok := rpcs.LookupRequest(req)
+	if !ok {
+		return nil
+	}
+
 	// Check to see if the request has a volume ID and if it is set.
 	// If the volume ID is not set then return an error.
-	if treq, ok := req.(interceptorHasVolumeID); ok {
-		if treq.GetVolumeId() == "" {
-			return status.Error(
-				codes.InvalidArgument, "required: VolumeID")
-		}
+	if volID, ok := m.VolumeID(req); ok && volID == "" {
+		return status.Error(
+			codes.InvalidArgument, "required: VolumeID")
 	}
 
 	// Check to see if the request has volume context and if they're
@@ -343,37 +452,8 @@ func (s *interceptor) validateRequest(
 		}
 	}
 
-	// Please leave requests that do not require explicit validation commented
-	// out for purposes of optimization. These requests are retained in this
-	// form to make it easy to add validation later if required.
-	//
-	switch tobj := req.(type) {
-	//
-	// Controller Service
-	//
-	case *csi.CreateVolumeRequest:
-		return s.validateCreateVolumeRequest(ctx, *tobj)
-	case *csi.DeleteVolumeRequest:
-		return s.validateDeleteVolumeRequest(ctx, *tobj)
-	case *csi.ControllerPublishVolumeRequest:
-		return s.validateControllerPublishVolumeRequest(ctx, *tobj)
-	case *csi.ControllerUnpublishVolumeRequest:
-		return s.validateControllerUnpublishVolumeRequest(ctx, *tobj)
-	case *csi.ValidateVolumeCapabilitiesRequest:
-		return s.validateValidateVolumeCapabilitiesRequest(ctx, *tobj)
-	case *csi.GetCapacityRequest:
-		return s.validateGetCapacityRequest(ctx, *tobj)
-		//
-		// Node Service
-		//
-	case *csi.NodeStageVolumeRequest:
-		return s.validateNodeStageVolumeRequest(ctx, *tobj)
-	case *csi.NodeUnstageVolumeRequest:
-		return s.validateNodeUnstageVolumeRequest(ctx, *tobj)
-	case *csi.NodePublishVolumeRequest:
-		return s.validateNodePublishVolumeRequest(ctx, *tobj)
-	case *csi.NodeUnpublishVolumeRequest:
-		return s.validateNodeUnpublishVolumeRequest(ctx, *tobj)
+	if validate, ok := requestValidators[m.Name]; ok {
+		return validate(s, ctx, req)
 	}
 
 	return nil
@@ -390,35 +470,17 @@ func (s *interceptor) validateResponse(
 
 	// Validate the field sizes.
 	if !s.opts.disableFieldLenCheck {
-		if err := validateFieldSizes(rep); err != nil {
+		if err := validateFieldSizes(ctx, rep, s.opts.fieldLimits); err != nil {
 			return err
 		}
 	}
 
-	switch tobj := rep.(type) {
-	//
-	// Controller Service
-	//
-	case *csi.CreateVolumeResponse:
-		return s.validateCreateVolumeResponse(ctx, *tobj)
-	case *csi.ControllerPublishVolumeResponse:
-		return s.validateControllerPublishVolumeResponse(ctx, *tobj)
-	case *csi.ListVolumesResponse:
-		return s.validateListVolumesResponse(ctx, *tobj)
-	case *csi.ControllerGetCapabilitiesResponse:
-		return s.validateControllerGetCapabilitiesResponse(ctx, *tobj)
-	//
-	// Identity Service
-	//
-	case *csi.GetPluginInfoResponse:
-		return s.validateGetPluginInfoResponse(ctx, *tobj)
-	//
-	// Node Service
-	//
-	case *csi.NodeGetInfoResponse:
-		return s.validateNodeGetInfoResponse(ctx, *tobj)
-	case *csi.NodeGetCapabilitiesResponse:
-		return s.validateNodeGetCapabilitiesResponse(ctx, *tobj)
+	m, ok := rpcs.LookupResponse(rep)
+	if !ok {
+		return nil
+	}
+	if validate, ok := responseValidators[m.Name]; ok {
+		return validate(s, ctx, rep)
 	}
 
 	return nil
@@ -432,11 +494,8 @@ func (s *interceptor) validateCreateVolumeRequest(
 		return status.Error(
 			codes.InvalidArgument, "required: Name")
 	}
-	if s.opts.requiresCtlrNewVolSecrets {
-		if len(req.Secrets) == 0 {
-			return status.Error(
-				codes.InvalidArgument, "required: Secrets")
-		}
+	if err := s.validateSecrets("CreateVolume", req.Secrets); err != nil {
+		return err
 	}
 
 	return validateVolumeCapabilitiesArg(req.VolumeCapabilities, true)
@@ -446,25 +505,15 @@ func (s *interceptor) validateDeleteVolumeRequest(
 	_ context.Context,
 	req csi.DeleteVolumeRequest,
 ) error {
-	if s.opts.requiresCtlrDelVolSecrets {
-		if len(req.Secrets) == 0 {
-			return status.Error(
-				codes.InvalidArgument, "required: Secrets")
-		}
-	}
-
-	return nil
+	return s.validateSecrets("DeleteVolume", req.Secrets)
 }
 
 func (s *interceptor) validateControllerPublishVolumeRequest(
 	_ context.Context,
 	req csi.ControllerPublishVolumeRequest,
 ) error {
-	if s.opts.requiresCtlrPubVolSecrets {
-		if len(req.Secrets) == 0 {
-			return status.Error(
-				codes.InvalidArgument, "required: Secrets")
-		}
+	if err := s.validateSecrets("ControllerPublishVolume", req.Secrets); err != nil {
+		return err
 	}
 
 	if req.NodeId == "" {
@@ -479,14 +528,7 @@ func (s *interceptor) validateControllerUnpublishVolumeRequest(
 	_ context.Context,
 	req csi.ControllerUnpublishVolumeRequest,
 ) error {
-	if s.opts.requiresCtlrUnpubVolSecrets {
-		if len(req.Secrets) == 0 {
-			return status.Error(
-				codes.InvalidArgument, "required: Secrets")
-		}
-	}
-
-	return nil
+	return s.validateSecrets("ControllerUnpublishVolume", req.Secrets)
 }
 
 func (s *interceptor) validateValidateVolumeCapabilitiesRequest(
@@ -512,11 +554,8 @@ func (s *interceptor) validateNodeStageVolumeRequest(
 			codes.InvalidArgument, "required: StagingTargetPath")
 	}
 
-	if s.opts.requiresNodeStgVolSecrets {
-		if len(req.Secrets) == 0 {
-			return status.Error(
-				codes.InvalidArgument, "required: Secrets")
-		}
+	if err := s.validateSecrets("NodeStageVolume", req.Secrets); err != nil {
+		return err
 	}
 
 	return validateVolumeCapabilityArg(req.VolumeCapability, true)
@@ -548,11 +587,8 @@ func (s *interceptor) validateNodePublishVolumeRequest(
 			codes.InvalidArgument, "required: TargetPath")
 	}
 
-	if s.opts.requiresNodePubVolSecrets {
-		if len(req.Secrets) == 0 {
-			return status.Error(
-				codes.InvalidArgument, "required: Secrets")
-		}
+	if err := s.validateSecrets("NodePublishVolume", req.Secrets); err != nil {
+		return err
 	}
 
 	return validateVolumeCapabilityArg(req.VolumeCapability, true)
@@ -570,6 +606,20 @@ func (s *interceptor) validateNodeUnpublishVolumeRequest(
 	return nil
 }
 
+// validateSecrets returns an error if the requests of the RPC with the
+// provided method name must contain secrets and the provided secrets are
+// empty.
+func (s *interceptor) validateSecrets(
+	method string,
+	secrets map[string]string,
+) error {
+	if s.opts.requiresSecrets[method] && len(secrets) == 0 {
+		return status.Error(
+			codes.InvalidArgument, "required: Secrets")
+	}
+	return nil
+}
+
 func (s *interceptor) validateCreateVolumeResponse(
 	_ context.Context,
 	rep csi.CreateVolumeResponse,
@@ -643,10 +693,10 @@ const (
 )
 
 func (s *interceptor) validateGetPluginInfoResponse(
-	_ context.Context,
+	ctx context.Context,
 	rep csi.GetPluginInfoResponse,
 ) error {
-	log.Debug("validateGetPluginInfoResponse: enter")
+	csictx.GetLogger(ctx).Debug("validateGetPluginInfoResponse: enter")
 
 	if rep.Name == "" {
 		return status.Error(codes.Internal, "empty: Name")
@@ -797,44 +847,59 @@ const (
 	maxPathLimit = EnvVarMaxPathLimit
 )
 
-func validateFieldSizes(msg interface{}) error {
+// fieldLimit returns the configured limit for the named field, or def if
+// the field has no override.
+func fieldLimit(limits map[string]int, name string, def int) int {
+	if v, ok := limits[name]; ok {
+		return v
+	}
+	return def
+}
+
+func validateFieldSizes(
+	ctx context.Context, msg interface{}, limits map[string]int,
+) error {
 	rv := reflect.ValueOf(msg).Elem()
 	tv := rv.Type()
 	nf := tv.NumField()
 	for i := 0; i < nf; i++ {
 		f := rv.Field(i)
+		name := tv.Field(i).Name
 		switch f.Kind() {
 		case reflect.String:
 			maxFieldLen := maxFieldString
-			if tv.Field(i).Name == "NodeId" {
+			if name == "NodeId" {
 				maxFieldLen = maxFieldNodeID
 			}
+			maxFieldLen = fieldLimit(limits, name, maxFieldLen)
 
 			if l := f.Len(); l > maxFieldLen {
 				return status.Errorf(
 					codes.InvalidArgument,
 					"exceeds size limit: %s: max=%d, size=%d",
-					tv.Field(i).Name, maxFieldLen, l)
+					name, maxFieldLen, l)
 			}
 		case reflect.Map:
 			if f.Len() == 0 {
 				continue
 			}
-			size := 0
-			maxFieldLen := maxFieldString
+			var (
+				size        = 0
+				maxEntryLen = fieldLimit(limits, name+"[]", maxFieldString)
+				maxMapSize  = fieldLimit(limits, name, maxFieldMap)
+			)
 			for _, k := range f.MapKeys() {
+				maxFieldLen := maxEntryLen
 				if k.Kind() == reflect.String {
 					if k.String() == "Path" {
-						maxFieldLen = setPathLimit(maxFieldString)
-					} else {
-						maxFieldLen = maxFieldString
+						maxFieldLen = setPathLimit(ctx, maxEntryLen)
 					}
 					kl := k.Len()
 					if kl > maxFieldLen {
 						return status.Errorf(
 							codes.InvalidArgument,
 							"exceeds size limit: %s[%s]: max=%d, size=%d",
-							tv.Field(i).Name, k.String(), maxFieldLen, kl)
+							name, k.String(), maxFieldLen, kl)
 					}
 					size = size + kl
 				}
@@ -844,23 +909,24 @@ func validateFieldSizes(msg interface{}) error {
 						return status.Errorf(
 							codes.InvalidArgument,
 							"exceeds size limit: %s[%s]=: max=%d, size=%d",
-							tv.Field(i).Name, k.String(), maxFieldLen, vl)
+							name, k.String(), maxFieldLen, vl)
 					}
 					size = size + vl
 				}
 			}
-			if size > maxFieldMap {
+			if size > maxMapSize {
 				return status.Errorf(
 					codes.InvalidArgument,
 					"exceeds size limit: %s: max=%d, size=%d",
-					tv.Field(i).Name, maxFieldMap, size)
+					name, maxMapSize, size)
 			}
 		}
 	}
 	return nil
 }
 
-func setPathLimit(defaultValue int) int {
+func setPathLimit(ctx context.Context, defaultValue int) int {
+	lg := csictx.GetLogger(ctx)
 	pathLimit := defaultValue
 	maxPathLimitStr, found := os.LookupEnv(maxPathLimit)
 	if found && maxPathLimitStr != "" {
@@ -868,14 +934,14 @@ func setPathLimit(defaultValue int) int {
 		if err == nil {
 			if maxPathLimit < pathLimit {
 				maxPathLimit = pathLimit
-				log.Debug("PathLimit set is less than the default value, using the default value for pathLimit: ", maxPathLimit)
+				lg.Debug("PathLimit set is less than the default value, using the default value for pathLimit", "pathLimit", maxPathLimit)
 				return maxPathLimit
 			}
-			log.Debug("PathLimit: ", maxPathLimit)
+			lg.Debug("PathLimit", "pathLimit", maxPathLimit)
 			return maxPathLimit
 		}
-		log.Errorf("Unable to convert maxPathLimit, using the default value for pathLimit: %d", pathLimit)
+		lg.Error("Unable to convert maxPathLimit, using the default value for pathLimit", "pathLimit", pathLimit)
 	}
-	log.Debug("PathLimit: ", pathLimit)
+	lg.Debug("PathLimit", "pathLimit", pathLimit)
 	return pathLimit
 }
@@ -20,6 +20,7 @@ package specvalidator
 
 import (
 	"context"
+	"expvar"
 	"os"
 	"reflect"
 	"strings"
@@ -1222,23 +1223,23 @@ func TestNodeGetPluginInfoResponse(t *testing.T) {
 
 func TestSetPathLimit(t *testing.T) {
 	// Test case: Default value
-	assert.Equal(t, setPathLimit(10), 10)
+	assert.Equal(t, setPathLimit(context.Background(), 10), 10)
 
 	// Test case: Custom value
 	os.Setenv(maxPathLimit, "20")
-	assert.Equal(t, setPathLimit(10), 20)
+	assert.Equal(t, setPathLimit(context.Background(), 10), 20)
 
 	// Test case: Invalid value
 	os.Setenv(maxPathLimit, "invalid")
-	assert.Equal(t, setPathLimit(10), 10)
+	assert.Equal(t, setPathLimit(context.Background(), 10), 10)
 
 	// Test case: Empty value
 	os.Setenv(maxPathLimit, "")
-	assert.Equal(t, setPathLimit(10), 10)
+	assert.Equal(t, setPathLimit(context.Background(), 10), 10)
 
 	// Test case: Value less than default
 	os.Setenv(maxPathLimit, "5")
-	assert.Equal(t, setPathLimit(10), 10)
+	assert.Equal(t, setPathLimit(context.Background(), 10), 10)
 }
 
 func TestValidateFieldSizes(t *testing.T) {
@@ -1330,7 +1331,7 @@ func TestValidateFieldSizes(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateFieldSizes(&tt.msg)
+			err := validateFieldSizes(context.Background(), &tt.msg, nil)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Equal(t, codes.InvalidArgument, status.Code(err))
@@ -1579,3 +1580,220 @@ func TestHandle(t *testing.T) {
 		})
 	}
 }
+
+func TestWarnOnly(t *testing.T) {
+	ctx := context.Background()
+	method := "/csi.v1.Node/NodeGetInfo"
+
+	invalidReq := &csi.DeleteVolumeRequest{}
+	invalidRep := &csi.NodeGetInfoResponse{}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		req     interface{}
+		rep     interface{}
+		key     string
+		wantErr bool
+	}{
+		{
+			name:    "invalid request enforced",
+			opts:    []Option{WithRequestValidation()},
+			req:     invalidReq,
+			rep:     &csi.DeleteVolumeResponse{},
+			key:     "request:" + method,
+			wantErr: true,
+		},
+		{
+			name:    "invalid request warn only",
+			opts:    []Option{WithRequestValidation(), WithRequestWarnOnly()},
+			req:     invalidReq,
+			rep:     &csi.DeleteVolumeResponse{},
+			key:     "request:" + method,
+			wantErr: false,
+		},
+		{
+			name:    "invalid response enforced",
+			opts:    []Option{WithResponseValidation()},
+			req:     &csi.NodeGetInfoRequest{},
+			rep:     invalidRep,
+			key:     "response:" + method,
+			wantErr: true,
+		},
+		{
+			name:    "invalid response warn only",
+			opts:    []Option{WithResponseValidation(), WithResponseWarnOnly()},
+			req:     &csi.NodeGetInfoRequest{},
+			rep:     invalidRep,
+			key:     "response:" + method,
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var before int64
+			if v, ok := Violations.Get(tt.key).(*expvar.Int); ok {
+				before = v.Value()
+			}
+
+			i := newSpecValidator(tt.opts...)
+			called := false
+			rep, err := i.handle(ctx, method, tt.req, func() (interface{}, error) {
+				called = true
+				return tt.rep, nil
+			})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, rep)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, called)
+				assert.Equal(t, tt.rep, rep)
+			}
+
+			v, ok := Violations.Get(tt.key).(*expvar.Int)
+			assert.True(t, ok)
+			assert.Equal(t, before+1, v.Value())
+		})
+	}
+}
+
+func TestWithFieldLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		msg     interface{}
+		wantErr string
+	}{
+		{
+			name: "default string limit",
+			msg: &csi.DeleteVolumeRequest{
+				VolumeId: strings.Repeat("v", maxFieldString+1),
+			},
+			wantErr: "exceeds size limit: VolumeId: max=192, size=193",
+		},
+		{
+			name: "raised string limit",
+			opts: []Option{WithFieldLimit("VolumeId", 256)},
+			msg: &csi.DeleteVolumeRequest{
+				VolumeId: strings.Repeat("v", maxFieldString+1),
+			},
+		},
+		{
+			name: "lowered string limit",
+			opts: []Option{WithFieldLimit("VolumeId", 8)},
+			msg: &csi.DeleteVolumeRequest{
+				VolumeId: "volume-id",
+			},
+			wantErr: "exceeds size limit: VolumeId: max=8, size=9",
+		},
+		{
+			name: "raised map limit with large map",
+			opts: []Option{WithFieldLimit("Parameters", 8192)},
+			msg: &csi.CreateVolumeRequest{
+				Name:       "volume",
+				Parameters: generateLargeMap(),
+			},
+		},
+		{
+			name: "default map limit with large map",
+			msg: &csi.CreateVolumeRequest{
+				Name:       "volume",
+				Parameters: generateLargeMap(),
+			},
+			wantErr: "exceeds size limit: Parameters: max=4096",
+		},
+		{
+			name: "raised map entry limit",
+			opts: []Option{WithFieldLimit("Parameters[]", 1024)},
+			msg: &csi.CreateVolumeRequest{
+				Name:       "volume",
+				Parameters: map[string]string{"key": strings.Repeat("a", 1000)},
+			},
+		},
+		{
+			name: "default map entry limit",
+			msg: &csi.CreateVolumeRequest{
+				Name:       "volume",
+				Parameters: map[string]string{"key": strings.Repeat("a", 1000)},
+			},
+			wantErr: "exceeds size limit: Parameters[key]=: max=192, size=1000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := newSpecValidator(tt.opts...)
+			err := validateFieldSizes(context.Background(), tt.msg, i.opts.fieldLimits)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRequiredSecretsOrder(t *testing.T) {
+	interceptor := NewServerSpecValidator(
+		WithRequestValidation(),
+		WithRequiresVolumeContext(),
+		WithRequiresControllerCreateVolumeSecrets(),
+		WithRequiresControllerPublishVolumeSecrets(),
+		WithRequiresNodeStageVolumeSecrets(),
+		WithRequiresNodePublishVolumeSecrets(),
+	)
+
+	// The secrets are checked after the other fields that preceded them
+	// when each RPC validated its own secrets.
+	tests := []struct {
+		name    string
+		method  string
+		req     interface{}
+		wantErr string
+	}{
+		{
+			name:    "CreateVolume",
+			method:  "/csi.v1.Controller/CreateVolume",
+			req:     &csi.CreateVolumeRequest{},
+			wantErr: "required: Name",
+		},
+		{
+			name:    "ControllerPublishVolume",
+			method:  "/csi.v1.Controller/ControllerPublishVolume",
+			req:     &csi.ControllerPublishVolumeRequest{VolumeId: "vol"},
+			wantErr: "required: VolumeContext",
+		},
+		{
+			name:   "NodeStageVolume",
+			method: "/csi.v1.Node/NodeStageVolume",
+			req: &csi.NodeStageVolumeRequest{
+				VolumeId:      "vol",
+				VolumeContext: map[string]string{"key": "value"},
+			},
+			wantErr: "required: StagingTargetPath",
+		},
+		{
+			name:   "NodePublishVolume",
+			method: "/csi.v1.Node/NodePublishVolume",
+			req: &csi.NodePublishVolumeRequest{
+				VolumeId:      "vol",
+				VolumeContext: map[string]string{"key": "value"},
+			},
+			wantErr: "required: TargetPath",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &grpc.UnaryServerInfo{FullMethod: tt.method}
+			_, err := interceptor(context.Background(), tt.req, info, nil)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Equal(t, tt.wantErr, status.Convert(err).Message())
+		})
+	}
+}
@@ -0,0 +1,208 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package gocsi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"google.golang.org/grpc"
+)
+
+// The names of the built-in middleware. Please see BuiltinMiddleware for
+// their default order.
+const (
+	MiddlewareContext       = "context"
+	MiddlewareRequestID     = "requestid"
+	MiddlewareRecovery      = "recovery"
+	MiddlewareAuthz         = "authz"
+	MiddlewareLogging       = "logging"
+	MiddlewareDeadline      = "deadline"
+	MiddlewareSpecValidator = "specvalidator"
+	MiddlewareRateLimit     = "ratelimit"
+	MiddlewarePluginInfo    = "plugininfo"
+	MiddlewareSerialVolume  = "serialvolume"
+	MiddlewareIdempotency   = "idempotency"
+	MiddlewareSecrets       = "secrets"
+	MiddlewareFaultInject   = "faultinject"
+)
+
+// BuiltinMiddleware returns the names of the built-in middleware in their
+// default order. The middleware are enabled by the SP's configuration;
+// the context injector is always enabled.
+func BuiltinMiddleware() []string {
+	return []string{
+		MiddlewareContext,
+		MiddlewareRequestID,
+		MiddlewareRecovery,
+		MiddlewareAuthz,
+		MiddlewareLogging,
+		MiddlewareDeadline,
+		MiddlewareSpecValidator,
+		MiddlewareRateLimit,
+		MiddlewarePluginInfo,
+		MiddlewareSerialVolume,
+		MiddlewareIdempotency,
+		MiddlewareSecrets,
+		MiddlewareFaultInject,
+	}
+}
+
+// Middleware is a named interceptor placed in the chain of the built-in
+// middleware.
+type Middleware struct {
+	// Name is the middleware's unique name. It may not be the name of a
+	// built-in middleware.
+	Name string
+
+	// Interceptor is the middleware's interceptor.
+	Interceptor grpc.UnaryServerInterceptor
+
+	// Before is the name of the middleware, built-in or not, that this
+	// middleware precedes in the chain.
+	Before string
+
+	// After is the name of the middleware, built-in or not, that this
+	// middleware follows in the chain. Exactly one of Before and After
+	// must be set unless the chain's order is set explicitly with the
+	// StoragePlugin's MiddlewareOrder field.
+	After string
+}
+
+// namedInterceptor is an entry in the chain of middleware. The interceptor
+// of a built-in middleware that is not enabled is nil.
+type namedInterceptor struct {
+	name  string
+	i     grpc.UnaryServerInterceptor
+	after string
+}
+
+// orderMiddleware returns the chain of the enabled built-in and custom
+// middleware. If order is not empty then it is the order of the chain,
+// and it is an error if the context injector is not first or an enabled
+// middleware is not in order, since omitting the context injector or the
+// panic recoverer, for example, would silently break the middleware that
+// depend on them. Otherwise the custom middleware are placed relative to
+// the default order of the built-in middleware.
+func orderMiddleware(
+	builtins map[string]grpc.UnaryServerInterceptor,
+	custom []Middleware,
+	order []string,
+) (chain []namedInterceptor, err error) {
+	names := BuiltinMiddleware()
+	byName := map[string]grpc.UnaryServerInterceptor{}
+	for _, n := range names {
+		byName[n] = builtins[n]
+	}
+	for _, m := range custom {
+		if m.Name == "" {
+			return nil, errors.New("middleware name is required")
+		}
+		if _, ok := byName[m.Name]; ok {
+			return nil, fmt.Errorf("duplicate middleware: %s", m.Name)
+		}
+		if m.Interceptor == nil {
+			return nil, fmt.Errorf("nil middleware: %s", m.Name)
+		}
+		byName[m.Name] = m.Interceptor
+		names = append(names, m.Name)
+	}
+
+	if len(order) > 0 {
+		seen := map[string]bool{}
+		for _, n := range order {
+			i, ok := byName[n]
+			if !ok {
+				return nil, fmt.Errorf("unknown middleware: %s", n)
+			}
+			if seen[n] {
+				return nil, fmt.Errorf("duplicate middleware: %s", n)
+			}
+			seen[n] = true
+			if i != nil {
+				chain = append(chain, namedInterceptor{name: n, i: i})
+			}
+		}
+		if order[0] != MiddlewareContext {
+			return nil, fmt.Errorf(
+				"middleware order: %s must be first", MiddlewareContext)
+		}
+		for _, n := range names {
+			if !seen[n] && byName[n] != nil {
+				return nil, fmt.Errorf(
+					"middleware order: missing enabled middleware: %s", n)
+			}
+		}
+		return chain, nil
+	}
+
+	for _, n := range BuiltinMiddleware() {
+		chain = append(chain, namedInterceptor{name: n, i: builtins[n]})
+	}
+
+	// Place the custom middleware in the order they are listed. A
+	// middleware placed relative to another custom middleware is placed
+	// once the other middleware is.
+	pending := custom
+	for len(pending) > 0 {
+		var next []Middleware
+		for _, m := range pending {
+			if (m.Before == "") == (m.After == "") {
+				return nil, fmt.Errorf(
+					"middleware %s: one of Before or After is required",
+					m.Name)
+			}
+			ref := m.Before + m.After
+			if _, ok := byName[ref]; !ok {
+				return nil, fmt.Errorf(
+					"middleware %s: unknown middleware: %s", m.Name, ref)
+			}
+			j := slices.IndexFunc(chain, func(e namedInterceptor) bool {
+				return e.name == ref
+			})
+			if j < 0 {
+				next = append(next, m)
+				continue
+			}
+			if m.After != "" {
+				// Follow the middleware already placed after the same
+				// middleware.
+				j++
+				for j < len(chain) && chain[j].after == ref {
+					j++
+				}
+			}
+			chain = slices.Insert(chain, j, namedInterceptor{
+				name: m.Name, i: m.Interceptor, after: m.After,
+			})
+		}
+		if len(next) == len(pending) {
+			return nil, fmt.Errorf(
+				"middleware %s: circular placement", next[0].Name)
+		}
+		pending = next
+	}
+
+	// Remove the built-in middleware that are not enabled.
+	chain = slices.DeleteFunc(chain, func(e namedInterceptor) bool {
+		return e.i == nil
+	})
+	return chain, nil
+}
@@ -0,0 +1,333 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package gocsi
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	csictx "github.com/dell/gocsi/context"
+	"github.com/dell/gocsi/utils/middleware"
+)
+
+// recordingInterceptor returns an interceptor that appends its name to
+// calls when it is invoked.
+func recordingInterceptor(name string, calls *[]string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		*calls = append(*calls, name)
+		return handler(ctx, req)
+	}
+}
+
+func chainNames(chain []namedInterceptor) []string {
+	names := make([]string, len(chain))
+	for i, e := range chain {
+		names[i] = e.name
+	}
+	return names
+}
+
+func TestOrderMiddleware(t *testing.T) {
+	var calls []string
+	builtins := map[string]grpc.UnaryServerInterceptor{}
+	for _, n := range []string{
+		MiddlewareContext,
+		MiddlewareLogging,
+		MiddlewareSerialVolume,
+		MiddlewareRecovery,
+	} {
+		builtins[n] = recordingInterceptor(n, &calls)
+	}
+	mw := func(name, before, after string) Middleware {
+		return Middleware{
+			Name:        name,
+			Interceptor: recordingInterceptor(name, &calls),
+			Before:      before,
+			After:       after,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		custom  []Middleware
+		order   []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "default order",
+			want: []string{"context", "recovery", "logging", "serialvolume"},
+		},
+		{
+			name: "before and after",
+			custom: []Middleware{
+				mw("auth", MiddlewareLogging, ""),
+				mw("metrics", MiddlewareSerialVolume, ""),
+				mw("audit", "", MiddlewareContext),
+				mw("trace", "", MiddlewareContext),
+			},
+			want: []string{
+				"context", "audit", "trace", "recovery", "auth",
+				"logging", "metrics", "serialvolume",
+			},
+		},
+		{
+			name: "relative to disabled built-in",
+			custom: []Middleware{
+				mw("quota", MiddlewareRateLimit, ""),
+			},
+			want: []string{
+				"context", "recovery", "logging", "quota", "serialvolume",
+			},
+		},
+		{
+			name: "relative to custom middleware",
+			custom: []Middleware{
+				mw("b", "", "a"),
+				mw("a", "", MiddlewareRecovery),
+			},
+			want: []string{
+				"context", "recovery", "a", "b", "logging", "serialvolume",
+			},
+		},
+		{
+			name: "order override",
+			custom: []Middleware{
+				mw("auth", "", ""),
+			},
+			order: []string{
+				MiddlewareContext, MiddlewareRecovery, "auth",
+				MiddlewareRateLimit, MiddlewareSerialVolume,
+				MiddlewareLogging,
+			},
+			want: []string{
+				"context", "recovery", "auth", "serialvolume", "logging",
+			},
+		},
+		{
+			name: "context not first in order",
+			order: []string{
+				MiddlewareLogging, MiddlewareContext,
+				MiddlewareSerialVolume, MiddlewareRecovery,
+			},
+			wantErr: "middleware order: context must be first",
+		},
+		{
+			name: "context missing from order",
+			order: []string{
+				MiddlewareLogging, MiddlewareSerialVolume, MiddlewareRecovery,
+			},
+			wantErr: "middleware order: context must be first",
+		},
+		{
+			name: "recovery missing from order",
+			order: []string{
+				MiddlewareContext, MiddlewareLogging, MiddlewareSerialVolume,
+			},
+			wantErr: "middleware order: missing enabled middleware: recovery",
+		},
+		{
+			name: "built-in missing from order",
+			order: []string{
+				MiddlewareContext, MiddlewareLogging, MiddlewareRecovery,
+			},
+			wantErr: "middleware order: missing enabled middleware: serialvolume",
+		},
+		{
+			name:   "custom missing from order",
+			custom: []Middleware{mw("auth", "", "")},
+			order: []string{
+				MiddlewareContext, MiddlewareLogging,
+				MiddlewareSerialVolume, MiddlewareRecovery,
+			},
+			wantErr: "middleware order: missing enabled middleware: auth",
+		},
+		{
+			name:    "unknown in order",
+			order:   []string{"metrics"},
+			wantErr: "unknown middleware: metrics",
+		},
+		{
+			name:    "duplicate in order",
+			order:   []string{MiddlewareLogging, MiddlewareLogging},
+			wantErr: "duplicate middleware: logging",
+		},
+		{
+			name:    "built-in name",
+			custom:  []Middleware{mw(MiddlewareLogging, MiddlewareContext, "")},
+			wantErr: "duplicate middleware: logging",
+		},
+		{
+			name:    "missing placement",
+			custom:  []Middleware{mw("auth", "", "")},
+			wantErr: "one of Before or After is required",
+		},
+		{
+			name:    "unknown placement",
+			custom:  []Middleware{mw("auth", "authn", "")},
+			wantErr: "unknown middleware: authn",
+		},
+		{
+			name: "circular placement",
+			custom: []Middleware{
+				mw("a", "b", ""),
+				mw("b", "", "a"),
+			},
+			wantErr: "circular placement",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := orderMiddleware(builtins, tt.custom, tt.order)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, chainNames(chain))
+
+			// The chain invokes the interceptors in order.
+			calls = nil
+			interceptors := make([]grpc.UnaryServerInterceptor, len(chain))
+			for i, e := range chain {
+				interceptors[i] = e.i
+			}
+			_, err = middleware.ChainUnaryServer(interceptors...)(
+				context.Background(), nil, &grpc.UnaryServerInfo{},
+				func(context.Context, interface{}) (interface{}, error) {
+					return nil, nil
+				})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, calls)
+		})
+	}
+}
+
+func TestInitInterceptorsMiddleware(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := csictx.WithLogger(context.Background(), slog.New(
+		slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	// The middleware placed after the context injector has the
+	// request's info.
+	var method string
+	sp := &StoragePlugin{
+		Middleware: []Middleware{{
+			Name: "auth",
+			Interceptor: func(
+				ctx context.Context,
+				req interface{},
+				_ *grpc.UnaryServerInfo,
+				handler grpc.UnaryHandler,
+			) (interface{}, error) {
+				info, _ := csictx.GetRequestInfo(ctx)
+				method = info.Method
+				return handler(ctx, req)
+			},
+			After: MiddlewareContext,
+		}},
+	}
+	sp.initInterceptors(ctx)
+	assert.Contains(t, buf.String(),
+		`msg="interceptor chain" interceptors="[context auth `)
+
+	_, err := middleware.ChainUnaryServer(sp.Interceptors...)(
+		context.Background(),
+		&csi.ProbeRequest{},
+		&grpc.UnaryServerInfo{FullMethod: "/csi.v1.Identity/Probe"},
+		func(context.Context, interface{}) (interface{}, error) {
+			return &csi.ProbeResponse{}, nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, "Probe", method)
+}
+
+func TestInitInterceptorsMiddlewareOrder(t *testing.T) {
+	originalOsExit := osExit
+	defer func() { osExit = originalOsExit }()
+	var code int
+	osExit = func(c int) { code = c }
+
+	buf := &bytes.Buffer{}
+	ctx := csictx.WithLogger(context.Background(),
+		slog.New(slog.NewTextHandler(buf, nil)))
+
+	// The panic recoverer is enabled but missing from the order.
+	sp := &StoragePlugin{
+		MiddlewareOrder: []string{MiddlewareContext, MiddlewareLogging},
+	}
+	sp.initInterceptors(ctx)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, buf.String(),
+		"middleware order: missing enabled middleware: ")
+}
+
+func TestBuiltinMiddleware(t *testing.T) {
+	names := BuiltinMiddleware()
+	assert.Equal(t, []string{
+		MiddlewareContext, MiddlewareRequestID, MiddlewareRecovery,
+		MiddlewareAuthz, MiddlewareLogging,
+	}, names[:5], "panics in the middleware are recovered, "+
+		"and calls are authorized before they are logged")
+}
+
+func TestInitInterceptorsRecovery(t *testing.T) {
+	ctx := csictx.WithLogger(context.Background(),
+		slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+
+	// A panic in a middleware that follows the built-in middleware is
+	// recovered.
+	sp := &StoragePlugin{
+		Middleware: []Middleware{{
+			Name: "panic",
+			Interceptor: func(
+				context.Context,
+				interface{},
+				*grpc.UnaryServerInfo,
+				grpc.UnaryHandler,
+			) (interface{}, error) {
+				panic("middleware panic")
+			},
+			After: MiddlewareFaultInject,
+		}},
+	}
+	sp.initInterceptors(ctx)
+
+	_, err := middleware.ChainUnaryServer(sp.Interceptors...)(
+		context.Background(),
+		&csi.ProbeRequest{},
+		&grpc.UnaryServerInfo{FullMethod: "/csi.v1.Identity/Probe"},
+		func(context.Context, interface{}) (interface{}, error) {
+			return &csi.ProbeResponse{}, nil
+		})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
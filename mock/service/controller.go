@@ -75,15 +75,11 @@ func (s *service) CreateVolume(
 		return &csi.CreateVolumeResponse{Volume: &v}, nil
 	}
 
-	// If no capacity is specified then use 100GiB
-	capacity := utils.Gib100
-	if cr := req.CapacityRange; cr != nil {
-		if rb := cr.RequiredBytes; rb > 0 {
-			capacity = rb
-		}
-		if lb := cr.LimitBytes; lb > 0 {
-			capacity = lb
-		}
+	// If no capacity is specified then the policy's default of 100GiB
+	// is used.
+	capacity, err := utils.ResolveCapacity(req.CapacityRange, capacityPolicy)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create the volume and add it to the service's in-mem volume slice.
@@ -604,25 +600,15 @@ func (s *service) ControllerExpandVolume(
 		return nil, status.Error(codes.NotFound, req.VolumeId)
 	}
 
-	var capacity int64
-
-	if cr := req.CapacityRange; cr != nil {
-		if rb := cr.RequiredBytes; rb > 0 {
-			capacity = rb
-		}
-		if lb := cr.LimitBytes; lb > 0 {
-			capacity = lb
-		}
-	}
-
-	if capacity < v.CapacityBytes {
-		return nil, status.Error(codes.OutOfRange, "requested new capacity smaller than existing")
+	capacity, err := utils.ResolveExpansionCapacity(
+		req.CapacityRange, capacityPolicy, v.CapacityBytes)
+	if err != nil {
+		return nil, err
 	}
-
-	v.CapacityBytes = capacity
+	s.vols[i].CapacityBytes = capacity
 
 	return &csi.ControllerExpandVolumeResponse{
-		CapacityBytes:         v.CapacityBytes,
+		CapacityBytes:         capacity,
 		NodeExpansionRequired: false,
 	}, nil
 }
@@ -43,6 +43,12 @@ var Manifest = map[string]string{
 	"url": "https://github.com/dell/gocsi/tree/master/mock",
 }
 
+// capacityPolicy describes the capacities of the mock service's volumes.
+var capacityPolicy = utils.CapacityPolicy{
+	DefaultBytes: utils.Gib100,
+	MaxBytes:     utils.Tib100,
+}
+
 // Service is the CSI Mock service provider.
 type MockServer interface {
 	csi.ControllerServer
@@ -213,7 +213,7 @@ var _ = Describe("Controller", func() {
 		}
 
 		Ω(vol).ShouldNot(BeNil())
-		Ω(vol.CapacityBytes).Should(Equal(limBytes))
+		Ω(vol.CapacityBytes).Should(Equal(reqBytes))
 		Ω(vol.VolumeId).Should(Equal(volID))
 		Ω(vol.VolumeContext["name"]).Should(Equal(volName))
 		return false
@@ -243,7 +243,7 @@ var _ = Describe("Controller", func() {
 			return true
 		}
 
-		Ω(bytes).Should(Equal(expBytes))
+		Ω(bytes).Should(Equal(limBytes))
 		return false
 	}
 
@@ -23,6 +23,7 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
@@ -132,5 +133,33 @@ var _ = Describe("Identity", func() {
 			Ω(rep).ShouldNot(BeNil())
 			Ω(rep.GetReady().GetValue()).To(Equal(true))
 		})
+
+		Context("With Request ID Injection", func() {
+			BeforeEach(func() {
+				ctx = csictx.WithEnviron(ctx,
+					[]string{
+						gocsi.EnvVarReqIDInjection + "=true",
+					})
+			})
+			It("Should Echo the Request ID", func() {
+				const id = "9b2b6e0e-3a8c-4b53-9a43-5d1f0c6a7e21"
+				var trailer metadata.MD
+				_, err := client.Probe(
+					metadata.AppendToOutgoingContext(
+						ctx, csictx.RequestIDKey, id),
+					&csi.ProbeRequest{},
+					grpc.Trailer(&trailer))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(trailer.Get(csictx.RequestIDKey)).Should(Equal([]string{id}))
+			})
+			It("Should Return a New Request ID", func() {
+				var trailer metadata.MD
+				_, err := client.Probe(
+					ctx, &csi.ProbeRequest{}, grpc.Trailer(&trailer))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(trailer.Get(csictx.RequestIDKey)).Should(HaveLen(1))
+				Ω(trailer.Get(csictx.RequestIDKey)[0]).ShouldNot(BeEmpty())
+			})
+		})
 	})
 })
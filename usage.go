@@ -33,10 +33,20 @@ GLOBAL OPTIONS
         pattern:
 
             * tcp://host:port
-            * unix:///path/to/file.sock.
+            * unix:///path/to/file.sock
+            * unix://@name, a Linux abstract UNIX socket
+            * vsock://cid:port, a VM socket on Linux
+            * fd://name, a socket passed by systemd socket activation
 
         If the network type is omitted then the value is assumed to be an
-        absolute or relative filesystem path to a UNIX socket file
+        absolute or relative filesystem path to a UNIX socket file. A stale
+        UNIX socket file left behind by a previous process is removed.
+
+        If sockets are passed to the process by systemd socket activation,
+        ex. LISTEN_FDS, then the activated socket with the endpoint's
+        address is used, or it is an error if there is no such socket.
+        CSI_ENDPOINT may be omitted to use the first activated socket. The file of an activated UNIX socket is owned
+        by systemd and is not removed when the process exits.
 
     X_CSI_MODE
         Specifies the service mode of the storage plug-in. Valid values are:
@@ -116,19 +126,80 @@ GLOBAL OPTIONS
 
     X_CSI_REP_LOGGING
         A flag that enables logging of outgoing responses to STDOUT.
+        Each response is logged with the time taken by the RPC and the
+        response's gRPC code.
 
         Enabling this option sets X_CSI_REQ_ID_INJECTION=true.
 
+    X_CSI_LOG_INCLUDE_METHODS
+        A comma-separated list of the only methods for which requests and
+        responses are logged. A method may be specified by its full gRPC
+        name, by SERVICE/METHOD, or by its name alone, ex.
+
+            CreateVolume, Node/NodePublishVolume
+
+    X_CSI_LOG_EXCLUDE_METHODS
+        A comma-separated list of methods for which requests and responses
+        are not logged, ex. Probe, NodeGetCapabilities. Methods are
+        specified the same way as for X_CSI_LOG_INCLUDE_METHODS. Exclusion
+        takes precedence over inclusion.
+
+    X_CSI_LOG_SAMPLE_RATES
+        The fraction of calls to a method that are logged, specified via
+        the following comma-separated format:
+
+            METHOD=RATE[, METHOD=RATE...]
+
+        RATE is a number from 0.0 (none) to 1.0 (all), and a METHOD of *
+        sets the rate for all other methods. Calls that fail are always
+        logged regardless of their sampling rate.
+
+    X_CSI_LOG_SLOW_CALL_THRESHOLD
+        A time.Duration string. Responses to RPCs that take longer than
+        this duration are logged at the WARN level instead of the INFO
+        level.
+
+        Only takes effect if Reply logging is enabled.
+
     X_CSI_LOG_DISABLE_VOL_CTX
         A flag that disables the logging of the VolumeContext field.
 
         Only takes effect if Request or Reply logging is enabled.
 
+    X_CSI_LOG_FORMAT
+        The format of request and response log records. Valid values
+        include:
+           * text
+           * json
+
+        When set to json each request and response is logged as a single
+        JSON object that includes the request ID, method, duration, gRPC
+        code, and the message encoded with protojson.
+
+        The default value is text.
+
+    X_CSI_LOG_REDACT_FIELDS
+        A comma-separated list of field paths that are redacted from JSON
+        request and response log records, ex.
+
+            parameters.password, publish_context.*token*
+
+        Each path is a series of dot-separated protobuf field names or map
+        keys. Segments may contain wildcards and the segment ** matches any
+        number of segments. Fields named secrets are always redacted.
+
     X_CSI_REQ_ID_INJECTION
         A flag that enables request ID injection. The ID is parsed from
         the incoming request's metadata with a key of "csi.requestid".
-        If no value for that key is found then a new request ID is
-        generated using an atomic sequence counter.
+        If no value for that key is found, or the value is longer than
+        128 bytes or not printable ASCII, then a new request ID is
+        generated using an atomic sequence counter. The request ID is
+        echoed back in the response's trailer metadata.
+
+    X_CSI_REQ_ID_UUID
+        A flag that generates request IDs that are UUIDs instead of
+        numbers. csictx.GetRequestID does not return UUIDs, so handlers
+        must use csictx.GetRequestIDString instead.
 
     X_CSI_SPEC_VALIDATION
         Setting X_CSI_SPEC_VALIDATION=true is the same as:
@@ -143,9 +214,36 @@ GLOBAL OPTIONS
         Invalid responses are marshalled into a gRPC error with a code
         of "Internal."
 
+    X_CSI_SPEC_REQ_VALIDATION_WARN_ONLY
+        A flag that causes invalid CSI request messages to be logged and
+        counted instead of rejected. The request is passed to the SP
+        unchanged.
+
+        Enabling this option sets X_CSI_SPEC_REQ_VALIDATION=true unless
+        that option is explicitly set to false.
+
+    X_CSI_SPEC_REP_VALIDATION_WARN_ONLY
+        A flag that causes invalid CSI response messages to be logged and
+        counted instead of marshalled into a gRPC error. The response is
+        returned to the client unchanged.
+
+        Enabling this option sets X_CSI_SPEC_REP_VALIDATION=true unless
+        that option is explicitly set to false.
+
     X_CSI_SPEC_DISABLE_LEN_CHECK
         A flag that disables validation of CSI message field lengths.
 
+    X_CSI_SPEC_FIELD_LIMITS
+        Overrides the permitted lengths of specific CSI message fields
+        using the following comma-separated format:
+
+            FIELD=MAX[, FIELD=MAX...]
+
+        FIELD is the Go name of a message field, ex. VolumeId. For map
+        fields the limit applies to the combined size of the map's keys
+        and values. Appending "[]" to the name of a map field, ex.
+        VolumeContext[], sets the limit for each key and value instead.
+
     X_CSI_REQUIRE_STAGING_TARGET_PATH
         A flag that enables treating the following fields as required:
             * NodePublishVolumeRequest.StagingTargetPath
@@ -283,5 +381,135 @@ GLOBAL OPTIONS
         A flag that indicates the TLS connection should not verify peer
         certificates.
 
+    X_CSI_IDEMPOTENCY
+        A flag that enables caching of the successful responses of
+        CreateVolume, CreateSnapshot, and ControllerPublishVolume. An
+        identical retry receives the cached response, and a different
+        request that reuses the name of a cached request, or the volume
+        and node IDs for ControllerPublishVolume, fails with the gRPC
+        error code AlreadyExists. Requests are compared without their
+        secrets.
+
+    X_CSI_IDEMPOTENCY_TTL
+        The amount of time for which a response is cached. The default
+        value is 5m.
+
+    X_CSI_IDEMPOTENCY_STORE
+        Where responses are cached. Valid values are "memory" and "etcd".
+        The etcd store uses the X_CSI_SERIAL_VOL_ACCESS_ETCD_* settings to
+        connect to etcd. The default value is "memory".
+
+    X_CSI_IDEMPOTENCY_ETCD_DOMAIN
+        The etcd key prefix under which responses are cached. The default
+        value is "/gocsi/idempotency".
+
+    X_CSI_RATE_LIMIT_MAX_IN_FLIGHT
+        Limits the number of concurrent calls to CSI methods using the
+        following comma-separated format:
+
+            METHOD=MAX[, METHOD=MAX...]
+
+        A METHOD of * sets the limit for all other methods.
+
+    X_CSI_RATE_LIMIT_RATES
+        Limits the rate of calls to CSI methods using the following
+        comma-separated format:
+
+            METHOD=RATE[:BURST][, METHOD=RATE[:BURST]...]
+
+        RATE is the number of calls per second and BURST is the number of
+        calls that may be made at once. A METHOD of * sets the limit for
+        all other methods.
+
+    X_CSI_RATE_LIMIT_WAIT
+        How long a call that exceeds a limit waits for the limit to be
+        satisfied. The value is a duration or "deadline" to wait until
+        the call's deadline. By default calls are rejected immediately.
+
+    X_CSI_RATE_LIMIT_CODE
+        The gRPC code returned for calls that exceed a limit. The default
+        value is ResourceExhausted.
+
+    X_CSI_RATE_LIMIT_KEY_PARAM
+        The name of a request parameter, ex. the name of a storage array,
+        whose values are limited separately. The value is read from the
+        request's Parameters or VolumeContext. The limits of at most 1024
+        values are tracked; once they are, calls with a new value share
+        the limits of calls without the parameter until an idle value's
+        limits are discarded.
+
+    X_CSI_DEADLINE_DEFAULTS
+        The timeouts applied to calls to CSI methods that do not have a
+        deadline using the following comma-separated format:
+
+            METHOD=DURATION[, METHOD=DURATION...]
+
+        A METHOD of * sets the timeout for all other methods. A call whose
+        deadline is exceeded receives DeadlineExceeded even if its handler
+        is still running.
+
+    X_CSI_DEADLINE_MAX
+        The maximum timeouts of calls to CSI methods. The format is the
+        same as for X_CSI_DEADLINE_DEFAULTS.
+
+    X_CSI_DEADLINE_ORPHAN_WARNING
+        How long after a call's deadline a warning is logged if the call's
+        handler is still running.
+
+    X_CSI_DISABLE_PANIC_RECOVERY
+        A flag that disables the recovery from panics in CSI handlers. By
+        default a panic is logged with its stack trace and the caller
+        receives an Internal error that includes the request ID. When
+        disabled, a panic crashes the process.
+
+    X_CSI_AUTHZ_POLICY
+        The path to a JSON policy file that maps caller identities to the
+        CSI methods they may call. Setting this environment variable
+        enables authorization, and calls that are not permitted by the
+        policy receive PermissionDenied. Callers are identified by the
+        verified certificates of mTLS connections, ex. "cn:NAME", or for
+        UNIX sockets by the user and group IDs of the peer process, ex.
+        "uid:0". The IDs of the peer process are not available if the
+        SP sets its own server credentials.
+
+    X_CSI_SECRETS_RESOLUTION
+        A flag that enables the resolution of references in the Secrets
+        field of CSI requests. A secret whose value is file:///PATH is
+        replaced with the content of the file, and a secret whose value
+        is env:VAR is replaced with the value of the environment variable.
+        Only the files and environment variables permitted by
+        X_CSI_SECRETS_FILE_DIRS and X_CSI_SECRETS_ENV_VARS may be
+        referenced. Resolved values are not seen by the logging
+        interceptors.
+
+    X_CSI_SECRETS_FILE_DIRS
+        A comma-separated list of directories. Only files in these
+        directories, after symbolic links are evaluated, may be referenced
+        by secrets. If not set, no file may be referenced.
+
+    X_CSI_SECRETS_ENV_VARS
+        A comma-separated list of the environment variables that may be
+        referenced by secrets. A name that ends with * permits the
+        environment variables with the preceding prefix, ex. CSI_SECRET_*.
+        If not set, no environment variable may be referenced.
+
+    X_CSI_FAULTS
+        A JSON encoded list of faults to inject into calls to CSI methods
+        for chaos testing. Setting this environment variable enables fault
+        injection. Each fault has a method, optional volumeID and name
+        patterns, a probability, and injects a latency, an error code, or
+        drops the response after the handler succeeds, ex.
+
+            [{"method":"NodePublishVolume","volumeID":"^vol-1",
+              "probability":0.5,"code":"UNAVAILABLE"}]
+
+        A fault without a probability is injected into every matching
+        call, and a fault with a probability of 0 is never injected.
+
+    X_CSI_FAULT_INJECTION_RPC
+        A flag that enables fault injection and registers the
+        gocsi.faultinject.v1.FaultInjector debug service, which allows
+        clients to change the injected faults at runtime.
+
 The flags -?,-h,-help may be used to print this screen.
 `
@@ -0,0 +1,203 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package csi
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// The environment variables used by systemd to pass sockets to a process.
+// Please see sd_listen_fds(3) for more information.
+const (
+	listenPIDEnv     = "LISTEN_PID"
+	listenFDsEnv     = "LISTEN_FDS"
+	listenFDNamesEnv = "LISTEN_FDNAMES"
+
+	// listenFDsStart is the first file descriptor passed by systemd.
+	listenFDsStart = 3
+)
+
+// ActivatedListener is a listener for a socket passed to the process by
+// systemd socket activation. The socket is owned by systemd, so its file
+// should not be removed when the listener is closed.
+type ActivatedListener struct {
+	net.Listener
+
+	// Name is the socket's name from the FileDescriptorName setting of
+	// the systemd socket unit.
+	Name string
+}
+
+// activatedFDs returns the number of sockets passed to the process by
+// systemd socket activation.
+func activatedFDs() int {
+	pid, err := strconv.Atoi(os.Getenv(listenPIDEnv))
+	if err != nil || pid != os.Getpid() {
+		return 0
+	}
+	n, err := strconv.Atoi(os.Getenv(listenFDsEnv))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// IsSocketActivated returns a flag indicating whether sockets were passed
+// to the process by systemd socket activation and have not yet been
+// retrieved with ActivatedListeners.
+func IsSocketActivated() bool {
+	return activatedFDs() > 0
+}
+
+// ActivatedListeners returns listeners for the sockets passed to the
+// process by systemd socket activation, in the order of their file
+// descriptors. The environment variables that describe the sockets are
+// unset so that child processes do not inherit them, so subsequent calls
+// return no listeners.
+func ActivatedListeners() ([]*ActivatedListener, error) {
+	n := activatedFDs()
+	if n == 0 {
+		return nil, nil
+	}
+	names := strings.Split(os.Getenv(listenFDNamesEnv), ":")
+	os.Unsetenv(listenPIDEnv)
+	os.Unsetenv(listenFDsEnv)
+	os.Unsetenv(listenFDNamesEnv)
+
+	listeners := make([]*ActivatedListener, 0, n)
+	for i := 0; i < n; i++ {
+		var name string
+		if i < len(names) {
+			name = names[i]
+		}
+
+		// The listener uses a duplicate of the file descriptor, so the
+		// original is closed.
+		fd := listenFDsStart + i
+		f := os.NewFile(uintptr(fd), name)
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf(
+				"invalid activated socket: fd=%d, name=%s: %v", fd, name, err)
+		}
+		listeners = append(listeners, &ActivatedListener{Listener: l, Name: name})
+	}
+	return listeners, nil
+}
+
+// listenActivated returns the activated listener with the provided name,
+// or the first activated listener if the name is empty. The other
+// activated listeners are closed.
+func listenActivated(name string) (net.Listener, error) {
+	l, _, err := selectActivatedListener(func(l *ActivatedListener) bool {
+		return name == "" || l.Name == name
+	})
+	if err != nil {
+		return nil, err
+	}
+	if l == nil {
+		return nil, fmt.Errorf("no activated socket: name=%s", name)
+	}
+	return l, nil
+}
+
+// selectActivatedListener returns the first activated listener for which
+// the provided function returns true. The other activated listeners are
+// closed. A nil listener is returned if no listener is selected, along
+// with the addresses of the activated listeners so that the caller may
+// describe them.
+func selectActivatedListener(
+	match func(*ActivatedListener) bool,
+) (*ActivatedListener, []string, error) {
+	listeners, err := ActivatedListeners()
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		selected *ActivatedListener
+		addrs    []string
+	)
+	for _, l := range listeners {
+		if selected == nil && match(l) {
+			selected = l
+			continue
+		}
+		a := l.Addr()
+		addrs = append(addrs, a.Network()+"://"+a.String())
+		l.Close()
+	}
+	return selected, addrs, nil
+}
+
+// sameListenAddr returns a flag indicating whether the listener's address
+// is the provided network address. The host of a TCP address is resolved,
+// so "localhost:5000" is the address of a listener on "127.0.0.1:5000",
+// and all unspecified hosts, ex. ":5000" and "0.0.0.0:5000", are the same.
+func sameListenAddr(l net.Listener, proto, addr string) bool {
+	la := l.Addr()
+	if !strings.HasPrefix(proto, la.Network()) {
+		return false
+	}
+	if la.Network() == "unix" && !IsAbstractSock(addr) {
+		return filepath.Clean(la.String()) == filepath.Clean(addr)
+	}
+	if ta, ok := la.(*net.TCPAddr); ok {
+		return sameTCPAddr(ta, proto, addr)
+	}
+	return la.String() == addr
+}
+
+// sameTCPAddr returns a flag indicating whether the provided TCP address
+// is the address of a listener on the provided network address.
+func sameTCPAddr(ta *net.TCPAddr, proto, addr string) bool {
+	host, szPort, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if port, err := net.LookupPort(proto, szPort); err != nil || port != ta.Port {
+		return false
+	}
+	unspecified := func(ip net.IP) bool {
+		return ip == nil || ip.IsUnspecified()
+	}
+	if host == "" {
+		return unspecified(ta.IP)
+	}
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		if ips, err = net.LookupIP(host); err != nil {
+			return false
+		}
+	}
+	for _, ip := range ips {
+		if ip.Equal(ta.IP) || (unspecified(ip) && unspecified(ta.IP)) {
+			return true
+		}
+	}
+	return false
+}
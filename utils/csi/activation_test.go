@@ -0,0 +1,153 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package csi_test
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	utils "github.com/dell/gocsi/utils/csi"
+)
+
+const (
+	activationTestEnv     = "GOCSI_TEST_ACTIVATION"
+	activationTestAddrEnv = "GOCSI_TEST_ACTIVATION_ADDR"
+	activationTestErrEnv  = "GOCSI_TEST_ACTIVATION_ERR"
+)
+
+// TestActivatedListenersProcess is run in a child process by
+// TestGetCSIEndpointListenerActivated with the activated sockets.
+func TestActivatedListenersProcess(t *testing.T) {
+	if os.Getenv(activationTestEnv) != "1" {
+		t.Skip("run by TestGetCSIEndpointListenerActivated")
+	}
+	RegisterTestingT(t)
+
+	// systemd sets LISTEN_PID to the pid of the process it starts.
+	os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	Expect(utils.IsSocketActivated()).To(BeTrue())
+
+	l, err := utils.GetCSIEndpointListener()
+	if msg := os.Getenv(activationTestErrEnv); msg != "" {
+		Expect(err).To(MatchError(ContainSubstring(msg)))
+	} else {
+		Expect(err).To(BeNil())
+		Expect(l).To(BeAssignableToTypeOf(&utils.ActivatedListener{}))
+		Expect(l.Addr().String()).To(Equal(os.Getenv(activationTestAddrEnv)))
+		Expect(l.Close()).To(Succeed())
+	}
+
+	// The sockets are not passed to child processes.
+	Expect(utils.IsSocketActivated()).To(BeFalse())
+	Expect(os.Getenv("LISTEN_FDS")).To(BeEmpty())
+}
+
+func TestGetCSIEndpointListenerActivated(t *testing.T) {
+	RegisterTestingT(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("socket activation is not supported")
+	}
+
+	sock := filepath.Join(t.TempDir(), "csi.sock")
+	ul, err := net.Listen("unix", sock)
+	Expect(err).To(BeNil())
+	defer ul.Close()
+	uf, err := ul.(*net.UnixListener).File()
+	Expect(err).To(BeNil())
+	defer uf.Close()
+
+	tl, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(BeNil())
+	defer tl.Close()
+	tf, err := tl.(*net.TCPListener).File()
+	Expect(err).To(BeNil())
+	defer tf.Close()
+	tcpAddr := tl.Addr().String()
+	tcpPort := strconv.Itoa(tl.Addr().(*net.TCPAddr).Port)
+
+	al, err := net.Listen("tcp", ":0")
+	Expect(err).To(BeNil())
+	defer al.Close()
+	af, err := al.(*net.TCPListener).File()
+	Expect(err).To(BeNil())
+	defer af.Close()
+	anyAddr := al.Addr().String()
+	anyPort := strconv.Itoa(al.Addr().(*net.TCPAddr).Port)
+
+	for _, c := range []struct {
+		endpoint, addr, err string
+	}{
+		{"", sock, ""},
+		{"fd://", sock, ""},
+		{"fd://other", tcpAddr, ""},
+		{"tcp://" + tcpAddr, tcpAddr, ""},
+		{"tcp://localhost:" + tcpPort, tcpAddr, ""},
+		{"tcp://:" + anyPort, anyAddr, ""},
+		{"tcp://0.0.0.0:" + anyPort, anyAddr, ""},
+		{"unix://" + sock, sock, ""},
+		{sock, sock, ""},
+
+		// The activated sockets are not silently replaced.
+		{"tcp://127.0.0.1:" + anyPort, "",
+			"endpoint=tcp://127.0.0.1:" + anyPort + ", activated=unix://" + sock},
+	} {
+		cmd := exec.Command(os.Args[0],
+			"-test.run=^TestActivatedListenersProcess$", "-test.v")
+		cmd.ExtraFiles = []*os.File{uf, tf, af}
+		cmd.Env = append(os.Environ(),
+			activationTestEnv+"=1",
+			activationTestAddrEnv+"="+c.addr,
+			activationTestErrEnv+"="+c.err,
+			utils.CSIEndpoint+"="+c.endpoint,
+			"LISTEN_FDS=3",
+			"LISTEN_FDNAMES=csi:other:any")
+		out, err := cmd.CombinedOutput()
+		Expect(err).To(BeNil(), fmt.Sprintf("%s: %s", c.endpoint, out))
+
+		// The file of an activated socket is not removed.
+		_, err = os.Stat(sock)
+		Expect(err).To(BeNil())
+	}
+}
+
+func TestActivatedListenersNotActivated(t *testing.T) {
+	RegisterTestingT(t)
+
+	// The sockets were passed to another process.
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+	t.Setenv("LISTEN_FDS", "1")
+	Expect(utils.IsSocketActivated()).To(BeFalse())
+	listeners, err := utils.ActivatedListeners()
+	Expect(err).To(BeNil())
+	Expect(listeners).To(BeEmpty())
+
+	_, err = utils.Listen("fd", "csi")
+	Expect(err).To(MatchError("no activated socket: name=csi"))
+
+	proto, addr, err := utils.ParseProtoAddr("fd://csi")
+	Expect(err).To(BeNil())
+	Expect(proto).To(Equal("fd"))
+	Expect(addr).To(Equal("csi"))
+}
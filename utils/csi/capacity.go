@@ -0,0 +1,163 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package csi
+
+import (
+	"math"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CapacityRounding describes which capacity is chosen from a capacity
+// range.
+type CapacityRounding int
+
+const (
+	// RoundUp resolves a capacity range to the smallest allocatable
+	// capacity that is at least the range's required bytes.
+	RoundUp CapacityRounding = iota
+
+	// RoundDown resolves a capacity range to the largest allocatable
+	// capacity that does not exceed the range's limit bytes. A range
+	// without limit bytes is resolved as with RoundUp.
+	RoundDown
+)
+
+// CapacityPolicy describes the capacities a storage backend is able to
+// allocate.
+type CapacityPolicy struct {
+	// DefaultBytes is the capacity used when a capacity range does not
+	// specify the required bytes. If zero then the minimum capacity is
+	// used.
+	DefaultBytes int64
+
+	// MinBytes is the minimum capacity of a volume.
+	MinBytes int64
+
+	// MaxBytes is the maximum capacity of a volume. Zero indicates there
+	// is no maximum.
+	MaxBytes int64
+
+	// AllocationUnit is the size of the units in which the backend
+	// allocates capacity, ex. utils.Gib. Every capacity is a multiple of
+	// the allocation unit. Zero indicates capacity is allocated in bytes.
+	AllocationUnit int64
+
+	// Rounding describes which allocatable capacity is chosen from a
+	// capacity range.
+	Rounding CapacityRounding
+}
+
+// ResolveCapacity returns the capacity of a new volume for the provided
+// capacity range, ex. from a CreateVolume request. The capacity is at
+// least the range's required bytes and at most its limit bytes, is a
+// multiple of the policy's allocation unit, and is within the policy's
+// minimum and maximum. A nil range resolves to the policy's default
+// capacity.
+//
+// An InvalidArgument error is returned if the range has negative values
+// and an OutOfRange error is returned if no allocatable capacity is in
+// the range.
+func ResolveCapacity(r *csi.CapacityRange, p CapacityPolicy) (int64, error) {
+	req, lim := r.GetRequiredBytes(), r.GetLimitBytes()
+	if req < 0 || lim < 0 {
+		return 0, status.Errorf(codes.InvalidArgument,
+			"invalid capacity range: required=%d, limit=%d", req, lim)
+	}
+	if lim > 0 && req > lim {
+		return 0, status.Errorf(codes.OutOfRange,
+			"required bytes %d exceed limit bytes %d", req, lim)
+	}
+	if p.MaxBytes > 0 && req > p.MaxBytes {
+		return 0, status.Errorf(codes.OutOfRange,
+			"required bytes %d exceed maximum capacity of %d bytes",
+			req, p.MaxBytes)
+	}
+	if lim > 0 && lim < p.MinBytes {
+		return 0, status.Errorf(codes.OutOfRange,
+			"limit bytes %d are less than minimum capacity of %d bytes",
+			lim, p.MinBytes)
+	}
+
+	var (
+		unit = max(p.AllocationUnit, 1)
+		lo   = max(req, p.MinBytes, 1)
+		hi   = int64(math.MaxInt64)
+	)
+	if lim > 0 {
+		hi = lim
+	}
+	if p.MaxBytes > 0 {
+		hi = min(hi, p.MaxBytes)
+	}
+
+	var c int64
+	switch {
+	case p.Rounding == RoundDown && lim > 0:
+		c = hi / unit * unit
+	case req == 0 && p.DefaultBytes > 0:
+		if c = roundUpCapacity(max(p.DefaultBytes, lo), unit); c > hi {
+			c = hi / unit * unit
+		}
+	default:
+		c = roundUpCapacity(lo, unit)
+	}
+
+	// Rounding to the allocation unit may move the capacity outside of
+	// the range.
+	if c < lo || c > hi {
+		return 0, status.Errorf(codes.OutOfRange,
+			"no capacity from %d to %d bytes is a multiple of "+
+				"the allocation unit of %d bytes", lo, hi, unit)
+	}
+	return c, nil
+}
+
+// ResolveExpansionCapacity returns the new capacity of a volume with the
+// provided current capacity for the capacity range of a
+// ControllerExpandVolume or NodeExpandVolume request. If the current
+// capacity is already in the range then it is returned, as the CSI
+// specification requires an expansion to a smaller or equal capacity to
+// succeed without changing the volume. Otherwise the range is resolved
+// with ResolveCapacity.
+func ResolveExpansionCapacity(
+	r *csi.CapacityRange, p CapacityPolicy, current int64,
+) (int64, error) {
+	req, lim := r.GetRequiredBytes(), r.GetLimitBytes()
+	if current >= req && req >= 0 && lim >= 0 {
+		if lim > 0 && current > lim {
+			return 0, status.Errorf(codes.OutOfRange,
+				"current capacity of %d bytes exceeds limit bytes %d",
+				current, lim)
+		}
+		return current, nil
+	}
+	return ResolveCapacity(r, p)
+}
+
+// roundUpCapacity rounds v up to a multiple of unit. If the result would
+// overflow then the largest multiple of unit is returned.
+func roundUpCapacity(v, unit int64) int64 {
+	if v > math.MaxInt64-unit+1 {
+		return math.MaxInt64 / unit * unit
+	}
+	return (v + unit - 1) / unit * unit
+}
@@ -0,0 +1,122 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package csi_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	utils "github.com/dell/gocsi/utils/csi"
+)
+
+func TestResolveCapacity(t *testing.T) {
+	RegisterTestingT(t)
+
+	p := utils.CapacityPolicy{
+		DefaultBytes:   8 * utils.Gib,
+		MinBytes:       utils.Gib,
+		MaxBytes:       64 * utils.Gib,
+		AllocationUnit: utils.Gib,
+	}
+	down := p
+	down.Rounding = utils.RoundDown
+
+	for _, c := range []struct {
+		name     string
+		policy   utils.CapacityPolicy
+		req, lim int64
+		want     int64
+		code     codes.Code
+	}{
+		{name: "default", policy: p, want: 8 * utils.Gib},
+		{name: "required", policy: p,
+			req: 2 * utils.Gib, want: 2 * utils.Gib},
+		{name: "rounded up", policy: p,
+			req: 2*utils.Gib + 1, want: 3 * utils.Gib},
+		{name: "minimum", policy: p,
+			req: utils.Mib, want: utils.Gib},
+		{name: "limit below default", policy: p,
+			lim: 4*utils.Gib + utils.Mib, want: 4 * utils.Gib},
+		{name: "range", policy: p,
+			req: 2 * utils.Gib, lim: 4 * utils.Gib, want: 2 * utils.Gib},
+		{name: "round down", policy: down,
+			req: 2 * utils.Gib, lim: 4*utils.Gib + 1, want: 4 * utils.Gib},
+		{name: "round down maximum", policy: down,
+			req: 2 * utils.Gib, lim: utils.Tib, want: 64 * utils.Gib},
+		{name: "round down without limit", policy: down,
+			req: 2*utils.Gib + 1, want: 3 * utils.Gib},
+		{name: "no allocation unit", policy: utils.CapacityPolicy{},
+			req: 1000, lim: 2000, want: 1000},
+		{name: "no policy", policy: utils.CapacityPolicy{}, want: 1},
+		{name: "negative", policy: p,
+			req: -1, code: codes.InvalidArgument},
+		{name: "required exceeds limit", policy: p,
+			req: 2 * utils.Gib, lim: utils.Gib, code: codes.OutOfRange},
+		{name: "required exceeds maximum", policy: p,
+			req: 65 * utils.Gib, code: codes.OutOfRange},
+		{name: "limit below minimum", policy: p,
+			lim: utils.Mib, code: codes.OutOfRange},
+		{name: "no multiple in range", policy: p,
+			req: 2*utils.Gib + 1, lim: 3*utils.Gib - 1, code: codes.OutOfRange},
+		{name: "overflow", policy: utils.CapacityPolicy{AllocationUnit: utils.Gib},
+			req: math.MaxInt64 - 1, code: codes.OutOfRange},
+	} {
+		got, err := utils.ResolveCapacity(&csi.CapacityRange{
+			RequiredBytes: c.req,
+			LimitBytes:    c.lim,
+		}, c.policy)
+		Expect(status.Code(err)).To(Equal(c.code), c.name)
+		Expect(got).To(Equal(c.want), c.name)
+	}
+
+	got, err := utils.ResolveCapacity(nil, p)
+	Expect(err).To(BeNil())
+	Expect(got).To(Equal(8 * utils.Gib))
+}
+
+func TestResolveExpansionCapacity(t *testing.T) {
+	RegisterTestingT(t)
+
+	p := utils.CapacityPolicy{AllocationUnit: utils.Gib}
+	current := 4 * utils.Gib
+
+	// A volume that already has the required capacity is not changed.
+	got, err := utils.ResolveExpansionCapacity(
+		&csi.CapacityRange{RequiredBytes: 2 * utils.Gib}, p, current)
+	Expect(err).To(BeNil())
+	Expect(got).To(Equal(current))
+
+	got, err = utils.ResolveExpansionCapacity(
+		&csi.CapacityRange{RequiredBytes: 5*utils.Gib + 1}, p, current)
+	Expect(err).To(BeNil())
+	Expect(got).To(Equal(6 * utils.Gib))
+
+	// A volume cannot be shrunk to the limit.
+	_, err = utils.ResolveExpansionCapacity(
+		&csi.CapacityRange{LimitBytes: 2 * utils.Gib}, p, current)
+	Expect(status.Code(err)).To(Equal(codes.OutOfRange))
+
+	_, err = utils.ResolveExpansionCapacity(
+		&csi.CapacityRange{RequiredBytes: -1}, p, current)
+	Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+}
@@ -0,0 +1,389 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package csi
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The Diff functions in this file return a description of each difference
+// between two objects, ex.
+//
+//	capacity_bytes: 1073741824 != 2147483648
+//	volume_context["pool"]: "gold" != <missing>
+//
+// The value from "a" is on the left and the value from "b" is on the right.
+// A nil slice is returned if the objects are equal.
+
+const missing = "<missing>"
+
+// AreVolumeCapabilitiesCompatible returns a flag indicating whether
+// the volume capability array "a" is compatible with "b". A true value
+// indicates that "a" and "b" are equivalent or "b" is a superset of "a",
+// i.e. every capability in "a" is equal to a capability in "b". Duplicate
+// capabilities are ignored. Please see DiffVolumeCapabilities for a
+// description of the capabilities that are not compatible.
+func AreVolumeCapabilitiesCompatible(
+	a, b []*csi.VolumeCapability,
+) (bool, error) {
+	if len(distinctVolumeCapabilities(a)) >
+		len(distinctVolumeCapabilities(b)) {
+		return false, status.Error(
+			codes.AlreadyExists,
+			"requested capabilities exceed existing")
+	}
+	return len(DiffVolumeCapabilities(a, b)) == 0, nil
+}
+
+// IsVolumeCapabilityCompatible returns a flag indicating whether
+// the volume capability "a" is compatible with the set "b". A true value
+// indicates that "a" and "b" are equivalent or "b" is a superset of "a".
+func IsVolumeCapabilityCompatible(
+	a *csi.VolumeCapability, b []*csi.VolumeCapability,
+) (bool, error) {
+	return AreVolumeCapabilitiesCompatible([]*csi.VolumeCapability{a}, b)
+}
+
+// DiffVolumeCapabilities returns a description of each capability in "a"
+// that is not equal to a capability in "b".
+func DiffVolumeCapabilities(a, b []*csi.VolumeCapability) []string {
+	var diff []string
+	for i, va := range a {
+		if !containsVolumeCapability(b, va) {
+			diff = append(diff, fmt.Sprintf(
+				"volume_capabilities[%d]: %v != %s", i, va, missing))
+		}
+	}
+	return diff
+}
+
+func containsVolumeCapability(
+	caps []*csi.VolumeCapability, c *csi.VolumeCapability,
+) bool {
+	return slices.ContainsFunc(caps, func(v *csi.VolumeCapability) bool {
+		return EqualVolumeCapability(v, c)
+	})
+}
+
+func distinctVolumeCapabilities(
+	caps []*csi.VolumeCapability,
+) []*csi.VolumeCapability {
+	var distinct []*csi.VolumeCapability
+	for _, c := range caps {
+		if !containsVolumeCapability(distinct, c) {
+			distinct = append(distinct, c)
+		}
+	}
+	return distinct
+}
+
+// EqualVolumeCapability returns a flag indicating if two csi.VolumeCapability
+// objects are equal. Mount flags are compared without regard to their order.
+// If a and b are both nil then true is returned.
+func EqualVolumeCapability(a, b *csi.VolumeCapability) bool {
+	return len(DiffVolumeCapability(a, b)) == 0
+}
+
+// DiffVolumeCapability returns a description of each difference between two
+// csi.VolumeCapability objects.
+func DiffVolumeCapability(a, b *csi.VolumeCapability) []string {
+	if a == nil || b == nil {
+		return diffNil("volume_capability", a == nil, b == nil)
+	}
+
+	var diff []string
+	diff = diffFormatted(diff, "access_mode",
+		accessModeString(a.AccessMode), accessModeString(b.AccessMode))
+	diff = diffFormatted(diff, "access_type",
+		accessTypeString(a), accessTypeString(b))
+
+	am, bm := a.GetMount(), b.GetMount()
+	if am == nil || bm == nil {
+		return diff
+	}
+	diff = diffField(diff, "mount.fs_type", am.FsType, bm.FsType)
+
+	// Compare sorted copies of the mount flags so the order of the
+	// original flags is not changed.
+	af := slices.Sorted(slices.Values(am.MountFlags))
+	bf := slices.Sorted(slices.Values(bm.MountFlags))
+	if !slices.Equal(af, bf) {
+		diff = append(diff, fmt.Sprintf(
+			"mount.mount_flags: %q != %q", af, bf))
+	}
+
+	return diffField(diff, "mount.volume_mount_group",
+		am.VolumeMountGroup, bm.VolumeMountGroup)
+}
+
+func accessModeString(m *csi.VolumeCapability_AccessMode) string {
+	if m == nil {
+		return "<nil>"
+	}
+	return m.Mode.String()
+}
+
+func accessTypeString(c *csi.VolumeCapability) string {
+	switch {
+	case c.GetBlock() != nil:
+		return "block"
+	case c.GetMount() != nil:
+		return "mount"
+	}
+	return "<nil>"
+}
+
+// EqualVolume returns a flag indicating if two csi.Volume
+// objects are equal. If a and b are both nil then true is returned.
+func EqualVolume(a, b *csi.Volume) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return CompareVolume(*a, *b) == 0
+}
+
+// CompareVolume compares two csi.Volume objects and returns a
+// negative number if a < b, a positive number if a > b, and zero if
+// a == b. The volumes' accessible topologies are compared without regard
+// to their order.
+func CompareVolume(a, b csi.Volume) int {
+	if c := strings.Compare(a.VolumeId, b.VolumeId); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.CapacityBytes, b.CapacityBytes); c != 0 {
+		return c
+	}
+	if c := compareMaps(a.VolumeContext, b.VolumeContext); c != 0 {
+		return c
+	}
+	if c := strings.Compare(
+		contentSourceString(a.ContentSource),
+		contentSourceString(b.ContentSource)); c != 0 {
+		return c
+	}
+	return slices.Compare(
+		topologyStrings(a.AccessibleTopology),
+		topologyStrings(b.AccessibleTopology))
+}
+
+// DiffVolume returns a description of each difference between two
+// csi.Volume objects.
+func DiffVolume(a, b *csi.Volume) []string {
+	if a == nil || b == nil {
+		return diffNil("volume", a == nil, b == nil)
+	}
+
+	var diff []string
+	diff = diffField(diff, "volume_id", a.VolumeId, b.VolumeId)
+	diff = diffField(diff, "capacity_bytes", a.CapacityBytes, b.CapacityBytes)
+	diff = diffMap(diff, "volume_context", a.VolumeContext, b.VolumeContext)
+	diff = diffFormatted(diff, "content_source",
+		contentSourceString(a.ContentSource),
+		contentSourceString(b.ContentSource))
+	return diffTopologies(diff, "accessible_topology",
+		a.AccessibleTopology, b.AccessibleTopology)
+}
+
+// EqualVolumeContentSource returns a flag indicating if two
+// csi.VolumeContentSource objects refer to the same snapshot or volume.
+// If a and b are both nil then true is returned.
+func EqualVolumeContentSource(a, b *csi.VolumeContentSource) bool {
+	return contentSourceString(a) == contentSourceString(b)
+}
+
+func contentSourceString(s *csi.VolumeContentSource) string {
+	switch {
+	case s == nil:
+		return "<nil>"
+	case s.GetSnapshot() != nil:
+		return "snapshot " + strconv.Quote(s.GetSnapshot().SnapshotId)
+	case s.GetVolume() != nil:
+		return "volume " + strconv.Quote(s.GetVolume().VolumeId)
+	}
+	return "<empty>"
+}
+
+// EqualTopology returns a flag indicating if two csi.Topology objects have
+// the same segments. If a and b are both nil then true is returned.
+func EqualTopology(a, b *csi.Topology) bool {
+	return topologyString(a) == topologyString(b)
+}
+
+// EqualTopologies returns a flag indicating if two lists of csi.Topology
+// objects contain the same topologies without regard to their order or
+// duplicates.
+func EqualTopologies(a, b []*csi.Topology) bool {
+	return slices.Equal(topologyStrings(a), topologyStrings(b))
+}
+
+// topologyString returns a string that uniquely identifies a topology's
+// segments, ex. "{rack=r1,zone=z1}".
+func topologyString(t *csi.Topology) string {
+	if t == nil {
+		return "<nil>"
+	}
+	keys := slices.Sorted(maps.Keys(t.Segments))
+	segs := make([]string, len(keys))
+	for i, k := range keys {
+		segs[i] = k + "=" + t.Segments[k]
+	}
+	return "{" + strings.Join(segs, ",") + "}"
+}
+
+// topologyStrings returns the sorted, distinct strings of the provided
+// topologies.
+func topologyStrings(topologies []*csi.Topology) []string {
+	s := make([]string, len(topologies))
+	for i, t := range topologies {
+		s[i] = topologyString(t)
+	}
+	slices.Sort(s)
+	return slices.Compact(s)
+}
+
+// EqualSnapshot returns a flag indicating if two csi.Snapshot objects are
+// equal. If a and b are both nil then true is returned.
+func EqualSnapshot(a, b *csi.Snapshot) bool {
+	return len(DiffSnapshot(a, b)) == 0
+}
+
+// DiffSnapshot returns a description of each difference between two
+// csi.Snapshot objects.
+func DiffSnapshot(a, b *csi.Snapshot) []string {
+	if a == nil || b == nil {
+		return diffNil("snapshot", a == nil, b == nil)
+	}
+
+	var diff []string
+	diff = diffField(diff, "snapshot_id", a.SnapshotId, b.SnapshotId)
+	diff = diffField(diff, "source_volume_id",
+		a.SourceVolumeId, b.SourceVolumeId)
+	diff = diffField(diff, "size_bytes", a.SizeBytes, b.SizeBytes)
+	diff = diffFormatted(diff, "creation_time",
+		timestampString(a), timestampString(b))
+	return diffField(diff, "ready_to_use", a.ReadyToUse, b.ReadyToUse)
+}
+
+func timestampString(s *csi.Snapshot) string {
+	if s.CreationTime == nil {
+		return "<nil>"
+	}
+	return s.CreationTime.AsTime().Format(time.RFC3339Nano)
+}
+
+// diffNil returns the difference between two objects of which at least
+// one is nil.
+func diffNil(name string, aNil, bNil bool) []string {
+	if aNil == bNil {
+		return nil
+	}
+	if aNil {
+		return []string{name + ": <nil> != <set>"}
+	}
+	return []string{name + ": <set> != <nil>"}
+}
+
+// diffField appends the difference between two field values to diff.
+// String values are quoted.
+func diffField[T comparable](diff []string, field string, a, b T) []string {
+	if a == b {
+		return diff
+	}
+	if _, ok := any(a).(string); ok {
+		return append(diff, fmt.Sprintf("%s: %q != %q", field, any(a), any(b)))
+	}
+	return append(diff, fmt.Sprintf("%s: %v != %v", field, a, b))
+}
+
+// diffFormatted appends the difference between two formatted field values
+// to diff.
+func diffFormatted(diff []string, field string, a, b string) []string {
+	if a == b {
+		return diff
+	}
+	return append(diff, fmt.Sprintf("%s: %s != %s", field, a, b))
+}
+
+// diffMap appends the differences between two maps to diff.
+func diffMap(diff []string, field string, a, b map[string]string) []string {
+	keys := slices.Sorted(maps.Keys(a))
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		va, aok := a[k]
+		vb, bok := b[k]
+		if aok && bok && va == vb {
+			continue
+		}
+		fa, fb := missing, missing
+		if aok {
+			fa = strconv.Quote(va)
+		}
+		if bok {
+			fb = strconv.Quote(vb)
+		}
+		diff = append(diff, fmt.Sprintf("%s[%q]: %s != %s", field, k, fa, fb))
+	}
+	return diff
+}
+
+// diffTopologies appends the topologies that are in only one of the
+// provided lists to diff.
+func diffTopologies(diff []string, field string, a, b []*csi.Topology) []string {
+	as, bs := topologyStrings(a), topologyStrings(b)
+	for _, s := range as {
+		if _, ok := slices.BinarySearch(bs, s); !ok {
+			diff = append(diff, fmt.Sprintf("%s: %s != %s", field, s, missing))
+		}
+	}
+	for _, s := range bs {
+		if _, ok := slices.BinarySearch(as, s); !ok {
+			diff = append(diff, fmt.Sprintf("%s: %s != %s", field, missing, s))
+		}
+	}
+	return diff
+}
+
+// compareMaps compares two maps by their sorted keys and values.
+func compareMaps(a, b map[string]string) int {
+	ak, bk := slices.Sorted(maps.Keys(a)), slices.Sorted(maps.Keys(b))
+	for i := 0; i < len(ak) && i < len(bk); i++ {
+		if c := strings.Compare(ak[i], bk[i]); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a[ak[i]], b[bk[i]]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(ak), len(bk))
+}
@@ -0,0 +1,208 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package csi_test
+
+import (
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	utils "github.com/dell/gocsi/utils/csi"
+)
+
+func mountCap(
+	mode csi.VolumeCapability_AccessMode_Mode, group string, flags ...string,
+) *csi.VolumeCapability {
+	c := utils.NewMountCapability(mode, "ext4", flags...)
+	c.GetMount().VolumeMountGroup = group
+	return c
+}
+
+func TestAreVolumeCapabilitiesCompatibleDuplicates(t *testing.T) {
+	RegisterTestingT(t)
+
+	a := mountCap(csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER, "")
+	b := utils.NewBlockCapability(
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER)
+
+	// Two matches of the same capability do not make up for one that is
+	// not supported.
+	c := mountCap(csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER, "")
+	ok, err := utils.AreVolumeCapabilitiesCompatible(
+		[]*csi.VolumeCapability{a, b}, []*csi.VolumeCapability{a, a, c})
+	Expect(err).To(BeNil())
+	Expect(ok).To(BeFalse())
+
+	// Duplicates in a supported set are compatible.
+	ok, err = utils.AreVolumeCapabilitiesCompatible(
+		[]*csi.VolumeCapability{a, a}, []*csi.VolumeCapability{a})
+	Expect(err).To(BeNil())
+	Expect(ok).To(BeTrue())
+
+	ok, err = utils.AreVolumeCapabilitiesCompatible(
+		[]*csi.VolumeCapability{a, a}, []*csi.VolumeCapability{b, a})
+	Expect(err).To(BeNil())
+	Expect(ok).To(BeTrue())
+
+	Expect(utils.DiffVolumeCapabilities(
+		[]*csi.VolumeCapability{a, b}, []*csi.VolumeCapability{a},
+	)).To(ConsistOf(HavePrefix("volume_capabilities[1]: ")))
+}
+
+func TestDiffVolumeCapability(t *testing.T) {
+	RegisterTestingT(t)
+
+	a := mountCap(csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+		"1000", "rw", "nosuid")
+	b := mountCap(csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+		"1000", "nosuid", "rw")
+	Expect(utils.DiffVolumeCapability(a, b)).To(BeNil())
+	Expect(a.GetMount().MountFlags).To(Equal([]string{"rw", "nosuid"}))
+
+	b.GetMount().VolumeMountGroup = "2000"
+	Expect(utils.EqualVolumeCapability(a, b)).To(BeFalse())
+	Expect(utils.DiffVolumeCapability(a, b)).To(Equal([]string{
+		`mount.volume_mount_group: "1000" != "2000"`,
+	}))
+
+	b = utils.NewBlockCapability(
+		csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY)
+	Expect(utils.DiffVolumeCapability(a, b)).To(Equal([]string{
+		"access_mode: SINGLE_NODE_WRITER != MULTI_NODE_READER_ONLY",
+		"access_type: mount != block",
+	}))
+
+	Expect(utils.DiffVolumeCapability(nil, nil)).To(BeNil())
+	Expect(utils.DiffVolumeCapability(a, nil)).To(Equal([]string{
+		"volume_capability: <set> != <nil>",
+	}))
+}
+
+func TestCompareNil(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Two nil objects are equal and have no differences, and a nil object
+	// is not equal to one that is not nil.
+	Expect(utils.EqualVolume(nil, nil)).To(BeTrue())
+	Expect(utils.EqualVolume(&csi.Volume{}, nil)).To(BeFalse())
+	Expect(utils.EqualVolume(nil, &csi.Volume{})).To(BeFalse())
+	Expect(utils.DiffVolume(nil, nil)).To(BeNil())
+	Expect(utils.DiffVolume(nil, &csi.Volume{})).To(Equal([]string{
+		"volume: <nil> != <set>",
+	}))
+
+	Expect(utils.EqualVolumeCapability(nil, nil)).To(BeTrue())
+	Expect(utils.EqualVolumeCapability(&csi.VolumeCapability{}, nil)).To(BeFalse())
+	Expect(utils.DiffVolumeCapability(nil, nil)).To(BeNil())
+
+	Expect(utils.EqualSnapshot(nil, nil)).To(BeTrue())
+	Expect(utils.EqualSnapshot(nil, &csi.Snapshot{})).To(BeFalse())
+	Expect(utils.DiffSnapshot(nil, nil)).To(BeNil())
+
+	Expect(utils.EqualVolumeContentSource(nil, nil)).To(BeTrue())
+	Expect(utils.EqualVolumeContentSource(
+		nil, &csi.VolumeContentSource{})).To(BeFalse())
+	Expect(utils.EqualTopology(nil, nil)).To(BeTrue())
+	Expect(utils.EqualTopology(nil, &csi.Topology{})).To(BeFalse())
+}
+
+func TestCompareVolumeContentSourceAndTopology(t *testing.T) {
+	RegisterTestingT(t)
+
+	snap := &csi.VolumeContentSource{
+		Type: &csi.VolumeContentSource_Snapshot{
+			Snapshot: &csi.VolumeContentSource_SnapshotSource{SnapshotId: "s1"},
+		},
+	}
+	vol := &csi.VolumeContentSource{
+		Type: &csi.VolumeContentSource_Volume{
+			Volume: &csi.VolumeContentSource_VolumeSource{VolumeId: "s1"},
+		},
+	}
+	z1 := &csi.Topology{Segments: map[string]string{"zone": "z1", "rack": "r1"}}
+	z2 := &csi.Topology{Segments: map[string]string{"zone": "z2"}}
+
+	a := &csi.Volume{VolumeId: "1", ContentSource: snap,
+		AccessibleTopology: []*csi.Topology{z1, z2}}
+	b := &csi.Volume{VolumeId: "1", ContentSource: snap,
+		AccessibleTopology: []*csi.Topology{z2, z1}}
+	Expect(utils.EqualVolume(a, b)).To(BeTrue())
+	Expect(utils.DiffVolume(a, b)).To(BeNil())
+
+	// A snapshot and a volume with the same ID are different sources.
+	b.ContentSource = vol
+	Expect(utils.EqualVolume(a, b)).To(BeFalse())
+	Expect(utils.EqualVolumeContentSource(snap, vol)).To(BeFalse())
+	Expect(utils.DiffVolume(a, b)).To(Equal([]string{
+		`content_source: snapshot "s1" != volume "s1"`,
+	}))
+
+	b.ContentSource = snap
+	b.AccessibleTopology = []*csi.Topology{z1}
+	Expect(utils.CompareVolume(*a, *b)).To(Equal(1))
+	Expect(utils.CompareVolume(*b, *a)).To(Equal(-1))
+	Expect(utils.DiffVolume(a, b)).To(Equal([]string{
+		"accessible_topology: {zone=z2} != <missing>",
+	}))
+
+	b.CapacityBytes = 10
+	b.VolumeContext = map[string]string{"pool": "gold"}
+	Expect(utils.DiffVolume(a, b)).To(Equal([]string{
+		"capacity_bytes: 0 != 10",
+		`volume_context["pool"]: <missing> != "gold"`,
+		"accessible_topology: {zone=z2} != <missing>",
+	}))
+
+	Expect(utils.EqualTopology(z1, &csi.Topology{
+		Segments: map[string]string{"rack": "r1", "zone": "z1"},
+	})).To(BeTrue())
+	Expect(utils.EqualTopologies(
+		[]*csi.Topology{z1, z2, z1}, []*csi.Topology{z2, z1})).To(BeTrue())
+}
+
+func TestDiffSnapshot(t *testing.T) {
+	RegisterTestingT(t)
+
+	a := &csi.Snapshot{
+		SnapshotId:     "s1",
+		SourceVolumeId: "1",
+		SizeBytes:      10,
+		CreationTime:   timestamppb.Now(),
+		ReadyToUse:     true,
+	}
+	b := &csi.Snapshot{
+		SnapshotId:     "s1",
+		SourceVolumeId: "1",
+		SizeBytes:      10,
+		CreationTime:   timestamppb.New(a.CreationTime.AsTime()),
+		ReadyToUse:     true,
+	}
+	Expect(utils.EqualSnapshot(a, b)).To(BeTrue())
+	Expect(utils.EqualSnapshot(nil, nil)).To(BeTrue())
+
+	b.SourceVolumeId = "2"
+	b.ReadyToUse = false
+	b.CreationTime = nil
+	Expect(utils.DiffSnapshot(a, b)).To(ConsistOf(
+		`source_volume_id: "1" != "2"`,
+		HavePrefix("creation_time: "),
+		"ready_to_use: true != false",
+	))
+}
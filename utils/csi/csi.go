@@ -29,11 +29,10 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
-	"sort"
 	"strings"
-	"sync"
+	"syscall"
+	"time"
 
-	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -66,26 +65,120 @@ func GetCSIEndpoint() (network, addr string, err error) {
 }
 
 // GetCSIEndpointListener returns the net.Listener for the endpoint
-// specified by the environment variable CSI_ENDPOINT.
+// specified by the environment variable CSI_ENDPOINT. If the endpoint is a
+// UNIX socket file that no server is listening on, ex. one left behind by
+// a server that crashed, then the file is removed before listening.
+//
+// If sockets were passed to the process by systemd socket activation then
+// the activated socket whose address is the endpoint is used, and an error
+// is returned if there is no such socket. The first activated socket is
+// used if CSI_ENDPOINT is not set. Please see ActivatedListeners.
 func GetCSIEndpointListener() (net.Listener, error) {
+	if IsSocketActivated() && emptyRX.MatchString(os.Getenv(CSIEndpoint)) {
+		return listenActivated("")
+	}
 	proto, addr, err := GetCSIEndpoint()
 	if err != nil {
 		return nil, err
 	}
+	if IsSocketActivated() && proto != "fd" {
+		l, addrs, err := selectActivatedListener(func(l *ActivatedListener) bool {
+			return sameListenAddr(l, proto, addr)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if l == nil {
+			return nil, fmt.Errorf(
+				"no activated socket for endpoint: endpoint=%s://%s, activated=%s",
+				proto, addr, strings.Join(addrs, ","))
+		}
+		return l, nil
+	}
+	if proto == "unix" {
+		if err := removeStaleSockFile(addr); err != nil {
+			return nil, err
+		}
+	}
+	return Listen(proto, addr)
+}
+
+// removeStaleSockFile removes the UNIX socket file at the provided path if
+// connections to it are refused. An error is returned if a server is
+// listening on the socket.
+func removeStaleSockFile(path string) error {
+	if IsAbstractSock(path) {
+		return nil
+	}
+	if fi, err := os.Lstat(path); err != nil || fi.Mode()&os.ModeSocket == 0 {
+		return nil
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("sock file is in use: %s", path)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale sock file: %s: %v", path, err)
+	}
+	return nil
+}
+
+// IsAbstractSock returns a flag indicating whether the provided UNIX
+// socket address is a Linux abstract socket, ex. "@csi", which has no file.
+func IsAbstractSock(addr string) bool {
+	return strings.HasPrefix(addr, "@")
+}
+
+// Listen announces on the provided network address, ex. one returned by
+// ParseProtoAddr. In addition to the networks supported by net.Listen,
+// the "vsock" network is supported on Linux, and the "fd" network returns
+// the socket with the provided name that was passed to the process by
+// systemd socket activation.
+func Listen(proto, addr string) (net.Listener, error) {
+	if strings.EqualFold(proto, "vsock") {
+		return listenVsock(addr)
+	}
+	if strings.EqualFold(proto, "fd") {
+		return listenActivated(addr)
+	}
 	return net.Listen(proto, addr)
 }
 
+// Dial connects to the provided network address, ex. one returned by
+// ParseProtoAddr. In addition to the networks supported by net.Dial, the
+// "vsock" network is supported on Linux.
+func Dial(ctx context.Context, proto, addr string) (net.Conn, error) {
+	if strings.EqualFold(proto, "vsock") {
+		return dialVsock(ctx, addr)
+	}
+	var d net.Dialer
+	return d.DialContext(ctx, proto, addr)
+}
+
 const (
-	protoAddrGuessPatt = `(?i)^(?:tcp|udp|ip|unix)[^:]*://`
+	protoAddrGuessPatt = `(?i)^(?:tcp|udp|ip|unix|vsock|passthrough|dns|fd)[^:]*://`
 
 	protoAddrExactPatt = `(?i)^((?:(?:tcp|udp|ip)[46]?)|` +
 		`(?:unix(?:gram|packet)?))://(.+)$`
+
+	protoAddrVsockPatt = `(?i)^vsock://(.+)$`
+
+	protoAddrTargetPatt = `(?i)^(?:passthrough|dns)://([^/]*)/(.+)$`
+
+	protoAddrFDPatt = `(?i)^fd://(.*)$`
 )
 
 var (
-	emptyRX          = regexp.MustCompile(`^\s*$`)
-	protoAddrGuessRX = regexp.MustCompile(protoAddrGuessPatt)
-	protoAddrExactRX = regexp.MustCompile(protoAddrExactPatt)
+	emptyRX           = regexp.MustCompile(`^\s*$`)
+	protoAddrGuessRX  = regexp.MustCompile(protoAddrGuessPatt)
+	protoAddrExactRX  = regexp.MustCompile(protoAddrExactPatt)
+	protoAddrVsockRX  = regexp.MustCompile(protoAddrVsockPatt)
+	protoAddrTargetRX = regexp.MustCompile(protoAddrTargetPatt)
+	protoAddrFDRX     = regexp.MustCompile(protoAddrFDPatt)
 )
 
 // ErrParseProtoAddrRequired occurs when an empty string is provided
@@ -93,7 +186,21 @@ var (
 var ErrParseProtoAddrRequired = errors.New(
 	"non-empty network address is required")
 
-// ParseProtoAddr parses a Golang network address.
+// ParseProtoAddr parses a Golang network address. In addition to the
+// networks supported by the net package, ex. "tcp://127.0.0.1:8080" or
+// "unix:///tmp/csi.sock", the following addresses are supported:
+//
+//   - "unix://@name" is a Linux abstract UNIX socket.
+//   - "vsock://cid:port" is a VM socket, ex. "vsock://3:10000". Please
+//     see Listen and Dial.
+//   - "passthrough:///host:port" and "dns:///host:port" are gRPC client
+//     targets and are parsed as TCP addresses.
+//   - "fd://name" is a socket passed to the process by systemd socket
+//     activation whose FileDescriptorName is name. The first activated
+//     socket is used if the name is empty, ex. "fd://".
+//
+// An address without a network, ex. "/tmp/csi.sock" or "@csi", is a UNIX
+// socket. The directory of a UNIX socket file must exist.
 func ParseProtoAddr(protoAddr string) (proto string, addr string, err error) {
 	if emptyRX.MatchString(protoAddr) {
 		return "", "", ErrParseProtoAddrRequired
@@ -102,33 +209,50 @@ func ParseProtoAddr(protoAddr string) (proto string, addr string, err error) {
 	// If the provided network address does not begin with one
 	// of the valid network protocols then treat the string as a
 	// file path.
-	//
-	// First check to see if the file exists at the specified path.
-	// If it does then assume it's a valid file path and return it.
-	//
-	// Otherwise, attempt to create the file. If the file can be created
-	// without error then remove the file and return the result a UNIX
-	// socket file path.
 	if !protoAddrGuessRX.MatchString(protoAddr) {
+		if IsAbstractSock(protoAddr) {
+			return "unix", protoAddr, nil
+		}
+
 		// If the file already exists then assume it's a valid sock
-		// file and return it.
-		if _, err := os.Stat(protoAddr); !os.IsNotExist(err) {
+		// file and return it. Otherwise the sock file's directory
+		// must exist so the file can be created.
+		_, err := os.Stat(protoAddr)
+		if err == nil {
 			return "unix", protoAddr, nil
 		}
-		f, err := os.Create(filepath.Clean(protoAddr))
+		if os.IsNotExist(err) {
+			dir := filepath.Dir(filepath.Clean(protoAddr))
+			var fi os.FileInfo
+			if fi, err = os.Stat(dir); err == nil && !fi.IsDir() {
+				err = fmt.Errorf("not a directory: %s", dir)
+			}
+		}
 		if err != nil {
 			return "", "", fmt.Errorf(
 				"invalid implied sock file: %s: %v", protoAddr, err)
 		}
-		if err := f.Close(); err != nil {
+		return "unix", protoAddr, nil
+	}
+
+	if m := protoAddrTargetRX.FindStringSubmatch(protoAddr); m != nil {
+		if m[1] != "" {
 			return "", "", fmt.Errorf(
-				"failed to verify network address as sock file: %s", protoAddr)
+				"unsupported target authority: %s", protoAddr)
 		}
-		if err := os.RemoveAll(protoAddr); err != nil {
+		return "tcp", m[2], nil
+	}
+
+	if m := protoAddrFDRX.FindStringSubmatch(protoAddr); m != nil {
+		return "fd", m[1], nil
+	}
+
+	if m := protoAddrVsockRX.FindStringSubmatch(protoAddr); m != nil {
+		if _, err := ParseVsockAddr(m[1]); err != nil {
 			return "", "", fmt.Errorf(
-				"failed to remove verified sock file: %s", protoAddr)
+				"invalid network address: %s: %v", protoAddr, err)
 		}
-		return "unix", protoAddr, nil
+		return "vsock", m[1], nil
 	}
 
 	// Parse the provided network address into the protocol and address parts.
@@ -323,215 +447,42 @@ func NewBlockCapability(
 }
 
 // PageVolumes issues one or more ListVolumes requests to retrieve
-// all available volumes, returning them over a Go channel.
+// all available volumes, returning them over a Go channel. Please see
+// NewVolumePager for an iterator with prefetching, limits, and resumption.
 func PageVolumes(
 	ctx context.Context,
 	client csi.ControllerClient,
 	req csi.ListVolumesRequest,
 	opts ...grpc.CallOption,
 ) (<-chan csi.Volume, <-chan error) {
-	var (
-		cvol = make(chan csi.Volume)
-		cerr = make(chan error)
-	)
-
-	// Execute the RPC in a goroutine, looping until there are no
-	// more volumes available.
-	go func() {
-		var (
-			wg     sync.WaitGroup
-			pages  int
-			cancel context.CancelFunc
-		)
-
-		// Get a cancellation context used to control the interaction
-		// between returning volumes and the possibility of an error.
-		ctx, cancel = context.WithCancel(ctx)
-
-		// waitAndClose closes the volume and error channels after all
-		// channel-dependent goroutines have completed their work
-		defer func() {
-			wg.Wait()
-			close(cerr)
-			close(cvol)
-			log.WithField("pages", pages).Debug("PageAllVolumes: exit")
-		}()
-
-		sendVolumes := func(res csi.ListVolumesResponse) {
-			// Loop over the volume entries until they're all gone
-			// or the context is cancelled.
-			var i int
-			for i = 0; i < len(res.Entries) && ctx.Err() == nil; i++ {
-
-				// Send the volume over the channel.
-				cvol <- *res.Entries[i].Volume
-
-				// Let the wait group know that this worker has completed
-				// its task.
-				wg.Done()
-			}
-			// If not all volumes have been sent over the channel then
-			// deduct the remaining number from the wait group.
-			if i != len(res.Entries) {
-				rem := len(res.Entries) - i
-				log.WithFields(map[string]interface{}{
-					"cancel":    ctx.Err(),
-					"remaining": rem,
-				}).Warn("PageAllVolumes: cancelled w unprocessed results")
-				wg.Add(-rem)
+	p := NewVolumePager(client, &req, WithCallOptions(opts...))
+	return pageChan(ctx, p.All(ctx),
+		func(e *csi.ListVolumesResponse_Entry) (csi.Volume, bool) {
+			if e.GetVolume() == nil {
+				return csi.Volume{}, false
 			}
-		}
-
-		// listVolumes returns true if there are more volumes to list.
-		listVolumes := func() bool {
-			// The wait group "wg" is blocked during the execution of
-			// this function.
-			wg.Add(1)
-			defer wg.Done()
-
-			res, err := client.ListVolumes(ctx, &req, opts...)
-			if err != nil {
-				cerr <- err
-
-				// Invoke the cancellation context function to
-				// ensure that work wraps up as quickly as possible.
-				cancel()
-
-				return false
-			}
-
-			// Add to the number of workers
-			wg.Add(len(res.Entries))
-
-			// Process the retrieved volumes.
-			go sendVolumes(*res)
-
-			// Set the request's starting token to the response's
-			// next token.
-			req.StartingToken = res.NextToken
-			return req.StartingToken != ""
-		}
-
-		// List volumes until there are no more volumes or the context
-		// is cancelled.
-		for {
-			if ctx.Err() != nil {
-				break
-			}
-			if !listVolumes() {
-				break
-			}
-			pages++ // TODO: What increments a page? I have tried with 10,000 mock volumes and still no paging happens.
-		}
-	}()
-
-	return cvol, cerr
+			return *e.Volume, true
+		})
 }
 
 // PageSnapshots issues one or more ListSnapshots requests to retrieve
-// all available snaphsots, returning them over a Go channel.
+// all available snaphsots, returning them over a Go channel. Please see
+// NewSnapshotPager for an iterator with prefetching, limits, and
+// resumption.
 func PageSnapshots(
 	ctx context.Context,
 	client csi.ControllerClient,
 	req csi.ListSnapshotsRequest,
 	opts ...grpc.CallOption,
 ) (<-chan csi.Snapshot, <-chan error) {
-	var (
-		csnap = make(chan csi.Snapshot)
-		cerr  = make(chan error)
-	)
-
-	// Execute the RPC in a goroutine, looping until there are no
-	// more snaphsots available.
-	go func() {
-		var (
-			wg     sync.WaitGroup
-			pages  int
-			cancel context.CancelFunc
-		)
-
-		// Get a cancellation context used to control the interaction
-		// between returning snaphsots and the possibility of an error.
-		ctx, cancel = context.WithCancel(ctx)
-
-		// waitAndClose closes the snaphsot and error channels after all
-		// channel-dependent goroutines have completed their work
-		defer func() {
-			wg.Wait()
-			close(cerr)
-			close(csnap)
-			log.WithField("pages", pages).Debug("PageAllSnapshots: exit")
-		}()
-
-		sendSnapshots := func(res csi.ListSnapshotsResponse) {
-			// Loop over the snaphsot entries until they're all gone
-			// or the context is cancelled.
-			var i int
-			for i = 0; i < len(res.Entries) && ctx.Err() == nil; i++ {
-
-				// Send the snaphsot over the channel.
-				csnap <- *res.Entries[i].Snapshot
-
-				// Let the wait group know that this worker has completed
-				// its task.
-				wg.Done()
-			}
-			// If not all snaphsots have been sent over the channel then
-			// deduct the remaining number from the wait group.
-			if i != len(res.Entries) {
-				rem := len(res.Entries) - i
-				log.WithFields(map[string]interface{}{
-					"cancel":    ctx.Err(),
-					"remaining": rem,
-				}).Warn("PageAllSnapshots: cancelled w unprocessed results")
-				wg.Add(-rem)
-			}
-		}
-
-		// listSnapshots returns true if there are more snaphsots to list.
-		listSnapshots := func() bool {
-			// The wait group "wg" is blocked during the execution of
-			// this function.
-			wg.Add(1)
-			defer wg.Done()
-
-			res, err := client.ListSnapshots(ctx, &req, opts...)
-			if err != nil {
-				cerr <- err
-
-				// Invoke the cancellation context function to
-				// ensure that work wraps up as quickly as possible.
-				cancel()
-
-				return false
+	p := NewSnapshotPager(client, &req, WithCallOptions(opts...))
+	return pageChan(ctx, p.All(ctx),
+		func(e *csi.ListSnapshotsResponse_Entry) (csi.Snapshot, bool) {
+			if e.GetSnapshot() == nil {
+				return csi.Snapshot{}, false
 			}
-
-			// Add to the number of workers
-			wg.Add(len(res.Entries))
-
-			// Process the retrieved snaphsots.
-			go sendSnapshots(*res)
-
-			// Set the request's starting token to the response's
-			// next token.
-			req.StartingToken = res.NextToken
-			return req.StartingToken != ""
-		}
-
-		// List snaphsots until there are no more snaphsots or the context
-		// is cancelled.
-		for {
-			if ctx.Err() != nil {
-				break
-			}
-			if !listSnapshots() {
-				break
-			}
-			pages++
-		}
-	}()
-
-	return csnap, cerr
+			return *e.Snapshot, true
+		})
 }
 
 // IsSuccess returns nil if the provided error is an RPC error with an error
@@ -560,140 +511,3 @@ func IsSuccess(err error, successCodes ...codes.Code) error {
 
 	return err
 }
-
-// AreVolumeCapabilitiesCompatible returns a flag indicating whether
-// the volume capability array "a" is compatible with "b". A true value
-// indicates that "a" and "b" are equivalent or "b" is a superset of "a".
-func AreVolumeCapabilitiesCompatible(
-	a, b []*csi.VolumeCapability,
-) (bool, error) {
-	if len(a) > len(b) {
-		return false, status.Error(
-			codes.AlreadyExists,
-			"requested capabilities exceed existing")
-	}
-
-	var i int
-
-	for _, va := range a {
-		for _, vb := range b {
-			if EqualVolumeCapability(va, vb) {
-				i++
-			}
-		}
-	}
-
-	return i >= len(a), nil
-}
-
-// IsVolumeCapabilityCompatible returns a flag indicating whether
-// the volume capability "a" is compatible with the set "b". A true value
-// indicates that "a" and "b" are equivalent or "b" is a superset of "a".
-func IsVolumeCapabilityCompatible(
-	a *csi.VolumeCapability, b []*csi.VolumeCapability,
-) (bool, error) {
-	return AreVolumeCapabilitiesCompatible([]*csi.VolumeCapability{a}, b)
-}
-
-// EqualVolumeCapability returns a flag indicating if two csi.VolumeCapability
-// objects are equal. If a and b are both nil then false is returned.
-func EqualVolumeCapability(a, b *csi.VolumeCapability) bool {
-	if a == nil || b == nil {
-		return false
-	}
-
-	// Compare access modes.
-	if a.AccessMode != nil && b.AccessMode == nil {
-		return false
-	}
-	if a.AccessMode == nil && b.AccessMode != nil {
-		return false
-	}
-	if a.AccessMode != nil && b.AccessMode != nil &&
-		a.AccessMode.Mode != b.AccessMode.Mode {
-		return false
-	}
-
-	// If both capabilities are block then return true.
-	if a.GetBlock() != nil && b.GetBlock() != nil {
-		return true
-	}
-
-	aMount := a.GetMount()
-	bMount := b.GetMount()
-	if aMount != nil && bMount != nil {
-
-		// If the filesystem types are incompatible then return false.
-		if aMount.FsType != bMount.FsType {
-			return false
-		}
-
-		// Compare the mount flags lengths.
-		if len(aMount.MountFlags) != len(bMount.MountFlags) {
-			return false
-		}
-
-		// Copy the mount flags to prevent the original order
-		// from changing due to the sort operation below.
-		af := append([]string{}, aMount.MountFlags...)
-		bf := append([]string{}, bMount.MountFlags...)
-
-		// Sort the mount flags prior to comparison.
-		sort.Strings(af)
-		sort.Strings(bf)
-
-		// Compare the mount flags.
-		for j := range af {
-			if af[j] != bf[j] {
-				return false
-			}
-		}
-
-		// The mount capabilities are compatible; return true.
-		return true
-	}
-
-	return false
-}
-
-// EqualVolume returns a flag indicating if two csi.Volume
-// objects are equal. If a and b are both nil then false is returned.
-func EqualVolume(a, b *csi.Volume) bool {
-	if a == nil || b == nil {
-		return false
-	}
-	return CompareVolume(*a, *b) == 0
-}
-
-// CompareVolume compares two csi.Volume objects and returns a
-// negative number if a < b, a positive number if a > b, and zero if
-// a == b.
-func CompareVolume(a, b csi.Volume) int {
-	if a.VolumeId < b.VolumeId {
-		return -1
-	}
-	if a.VolumeId > b.VolumeId {
-		return 1
-	}
-	if a.CapacityBytes < b.CapacityBytes {
-		return -1
-	}
-	if a.CapacityBytes > b.CapacityBytes {
-		return 1
-	}
-	if len(a.VolumeContext) < len(b.VolumeContext) {
-		return -1
-	}
-	if len(a.VolumeContext) > len(b.VolumeContext) {
-		return 1
-	}
-	for k, v := range a.VolumeContext {
-		if v < b.VolumeContext[k] {
-			return -1
-		}
-		if v > b.VolumeContext[k] {
-			return 1
-		}
-	}
-	return 0
-}
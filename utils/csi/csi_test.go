@@ -24,7 +24,6 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"path/filepath"
 	"testing"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
@@ -152,13 +151,13 @@ var _ = Describe("GetCSIEndpoint", func() {
 		shouldBeInvalid := func() {
 			Ω(err).Should(HaveOccurred())
 			Ω(err.Error()).Should(
-				// os.Create() error for an invalid file name is different when running the test
-				// in Linux or Windows. The following substring indicates enough that file creation
-				// was attempted and failed, so we haven't allowed a bad file name to be used as the endpoint.
-				ContainSubstring(fmt.Sprintf(
-					"invalid implied sock file: %s: open %s: ",
-					expEndpoint, filepath.Clean(expEndpoint))),
+				// The sock file's directory does not exist. The file
+				// is not created to verify the path.
+				HavePrefix(fmt.Sprintf(
+					"invalid implied sock file: %s: ", expEndpoint)),
 			)
+			_, statErr := os.Stat(expEndpoint)
+			Ω(os.IsNotExist(statErr)).Should(BeTrue())
 		}
 		Context("Xtcp5:/localhost:5000", func() {
 			It("Should Be An Invalid Implied Sock File", shouldBeInvalid)
@@ -358,7 +357,8 @@ var _ = Describe("EqualVolumeCapability", func() {
 		a = nil
 		Ω(utils.EqualVolumeCapability(nil, b)).Should(BeFalse())
 		b = nil
-		Ω(utils.EqualVolumeCapability(a, b)).Should(BeFalse())
+		// Two nil capabilities are equal.
+		Ω(utils.EqualVolumeCapability(a, b)).Should(BeTrue())
 
 		aAT := &csi.VolumeCapability_Mount{
 			Mount: &csi.VolumeCapability_MountVolume{
@@ -764,7 +764,7 @@ func TestEqualVolume(t *testing.T) {
 
 	// Test case: Both volumes are nil
 	b = nil
-	Ω(utils.EqualVolume(a, b)).Should(BeFalse())
+	Ω(utils.EqualVolume(a, b)).Should(BeTrue())
 }
 
 func TestGetCSIEndpointListener(t *testing.T) {
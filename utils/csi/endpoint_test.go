@@ -0,0 +1,160 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package csi_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	utils "github.com/dell/gocsi/utils/csi"
+)
+
+func TestParseProtoAddrForms(t *testing.T) {
+	RegisterTestingT(t)
+
+	for _, c := range []struct {
+		protoAddr, proto, addr string
+	}{
+		{"unix://@csi", "unix", "@csi"},
+		{"@csi", "unix", "@csi"},
+		{"vsock://3:10000", "vsock", "3:10000"},
+		{"vsock://any:10000", "vsock", "any:10000"},
+		{"passthrough:///localhost:5000", "tcp", "localhost:5000"},
+		{"dns:///csi.example.com:5000", "tcp", "csi.example.com:5000"},
+	} {
+		proto, addr, err := utils.ParseProtoAddr(c.protoAddr)
+		Expect(err).To(BeNil(), c.protoAddr)
+		Expect(proto).To(Equal(c.proto), c.protoAddr)
+		Expect(addr).To(Equal(c.addr), c.protoAddr)
+	}
+
+	for _, protoAddr := range []string{
+		"vsock://3",
+		"vsock://x:10000",
+		"vsock://3:4294967296",
+		"dns://8.8.8.8/csi.example.com:5000",
+	} {
+		_, _, err := utils.ParseProtoAddr(protoAddr)
+		Expect(err).ToNot(BeNil(), protoAddr)
+	}
+
+	// A file path whose parent is not a directory is invalid.
+	file := filepath.Join(t.TempDir(), "file")
+	Expect(os.WriteFile(file, nil, 0o600)).To(Succeed())
+	_, _, err := utils.ParseProtoAddr(filepath.Join(file, "csi.sock"))
+	Expect(err).To(MatchError(ContainSubstring("not a directory")))
+
+	addr, err := utils.ParseVsockAddr("any:any")
+	Expect(err).To(BeNil())
+	Expect(addr).To(Equal(&utils.VsockAddr{
+		CID:  utils.VsockCIDAny,
+		Port: utils.VsockPortAny,
+	}))
+	Expect(addr.String()).To(Equal("any:any"))
+}
+
+func TestGetCSIEndpointListenerStaleSock(t *testing.T) {
+	RegisterTestingT(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("UNIX sockets are not supported")
+	}
+	defer os.Unsetenv(utils.CSIEndpoint)
+
+	sock := filepath.Join(t.TempDir(), "csi.sock")
+	os.Setenv(utils.CSIEndpoint, "unix://"+sock)
+
+	// Leave a sock file behind as a crashed server would.
+	l, err := net.Listen("unix", sock)
+	Expect(err).To(BeNil())
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	Expect(l.Close()).To(Succeed())
+	_, err = os.Stat(sock)
+	Expect(err).To(BeNil())
+
+	l, err = utils.GetCSIEndpointListener()
+	Expect(err).To(BeNil())
+	defer l.Close()
+
+	// A sock file with a listening server is not removed.
+	_, err = utils.GetCSIEndpointListener()
+	Expect(err).To(MatchError(ContainSubstring("sock file is in use")))
+	_, err = os.Stat(sock)
+	Expect(err).To(BeNil())
+}
+
+func TestListenAbstractSock(t *testing.T) {
+	RegisterTestingT(t)
+	if runtime.GOOS != "linux" {
+		t.Skip("abstract sockets are only supported on Linux")
+	}
+
+	proto, addr, err := utils.ParseProtoAddr("unix://@gocsi-test")
+	Expect(err).To(BeNil())
+	l, err := utils.Listen(proto, addr)
+	Expect(err).To(BeNil())
+	defer l.Close()
+
+	conn, err := utils.Dial(context.Background(), proto, addr)
+	Expect(err).To(BeNil())
+	Expect(conn.Close()).To(Succeed())
+}
+
+func TestListenVsock(t *testing.T) {
+	RegisterTestingT(t)
+
+	// The local CID allows a host to connect to itself.
+	l, err := utils.Listen("vsock", "1:any")
+	if err != nil {
+		t.Skipf("vsock is not available: %v", err)
+	}
+	defer l.Close()
+	addr := l.Addr().(*utils.VsockAddr)
+	Expect(addr.Port).ToNot(Equal(utils.VsockPortAny))
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 4)
+		if n, err := conn.Read(buf); err == nil {
+			_, _ = conn.Write(buf[:n])
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := utils.Dial(ctx, "vsock", addr.String())
+	if err != nil {
+		t.Skipf("vsock loopback is not available: %v", err)
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte("ping"))
+	Expect(err).To(BeNil())
+	buf := make([]byte, 4)
+	_, err = conn.Read(buf)
+	Expect(err).To(BeNil())
+	Expect(string(buf)).To(Equal("ping"))
+}
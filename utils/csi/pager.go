@@ -0,0 +1,309 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package csi
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc"
+
+	csictx "github.com/dell/gocsi/context"
+)
+
+// PageFunc retrieves the page of items that begins at the provided token.
+// An empty token retrieves the first page. The returned next token is
+// empty if there are no more pages.
+type PageFunc[T any] func(
+	ctx context.Context, token string) (items []T, nextToken string, err error)
+
+// Cursor is a position in a paginated list of items. A cursor may be saved
+// and later used to resume paging with WithCursor.
+type Cursor struct {
+	// Token is the token of the page that contains the next item.
+	Token string
+
+	// Offset is the number of items of the page that have already been
+	// returned.
+	Offset int
+}
+
+// PagerOption configures a Pager.
+type PagerOption func(*pagerOpts)
+
+type pagerOpts struct {
+	prefetch bool
+	limit    int
+	cursor   Cursor
+	callOpts []grpc.CallOption
+}
+
+// WithPrefetch is a PagerOption that retrieves the next page concurrently
+// while the items of the current page are consumed.
+func WithPrefetch() PagerOption {
+	return func(o *pagerOpts) {
+		o.prefetch = true
+	}
+}
+
+// WithLimit is a PagerOption that limits the total number of items
+// returned by the pager. A limit less than one disables the limit.
+func WithLimit(n int) PagerOption {
+	return func(o *pagerOpts) {
+		o.limit = n
+	}
+}
+
+// WithStartingToken is a PagerOption that begins paging at the page with
+// the provided token, ex. a starting token provided by a user.
+func WithStartingToken(token string) PagerOption {
+	return func(o *pagerOpts) {
+		o.cursor = Cursor{Token: token}
+	}
+}
+
+// WithCursor is a PagerOption that resumes paging at the provided cursor,
+// ex. one returned by the Cursor method of another Pager.
+func WithCursor(c Cursor) PagerOption {
+	return func(o *pagerOpts) {
+		o.cursor = c
+	}
+}
+
+// WithCallOptions is a PagerOption that sets the gRPC call options used
+// by the pagers returned by NewVolumePager and NewSnapshotPager.
+func WithCallOptions(opts ...grpc.CallOption) PagerOption {
+	return func(o *pagerOpts) {
+		o.callOpts = opts
+	}
+}
+
+// Pager iterates over the items of a paginated RPC, ex. ListVolumes.
+type Pager[T any] struct {
+	fetch PageFunc[T]
+	opts  pagerOpts
+
+	mu     sync.Mutex
+	cursor Cursor
+}
+
+// NewPager returns a new Pager that uses the provided function to
+// retrieve pages of items.
+func NewPager[T any](fetch PageFunc[T], opts ...PagerOption) *Pager[T] {
+	p := &Pager[T]{fetch: fetch}
+	for _, setOpt := range opts {
+		setOpt(&p.opts)
+	}
+	p.cursor = p.opts.cursor
+	return p
+}
+
+// Cursor returns the position of the item after the last item returned
+// by the pager.
+func (p *Pager[T]) Cursor() Cursor {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cursor
+}
+
+func (p *Pager[T]) setCursor(c Cursor) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cursor = c
+}
+
+// page is the result of retrieving a page.
+type page[T any] struct {
+	items []T
+	next  string
+	err   error
+}
+
+// All returns an iterator over the pager's items, beginning at the pager's
+// cursor. If a page cannot be retrieved then the iterator yields the error
+// and stops. Retrieval stops when the caller stops iterating or the
+// provided context is cancelled.
+func (p *Pager[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var (
+			cursor  = p.Cursor()
+			token   = cursor.Token
+			skip    = cursor.Offset
+			items   int
+			pages   int
+			pending <-chan page[T]
+		)
+		defer func() {
+			csictx.GetLogger(ctx).Debug("paging complete",
+				"pages", pages,
+				"items", items)
+		}()
+
+		fetch := func(token string) page[T] {
+			v, next, err := p.fetch(ctx, token)
+			return page[T]{items: v, next: next, err: err}
+		}
+		prefetch := func(token string) <-chan page[T] {
+			c := make(chan page[T], 1)
+			go func() { c <- fetch(token) }()
+			return c
+		}
+		limited := func() bool {
+			return p.opts.limit > 0 && items >= p.opts.limit
+		}
+
+		for !limited() {
+			var pg page[T]
+			if pending != nil {
+				pg, pending = <-pending, nil
+			} else {
+				pg = fetch(token)
+			}
+			if pg.err == nil && pg.next != "" && pg.next == token {
+				pg.err = fmt.Errorf("paging: next token repeats: %s", token)
+			}
+			if pg.err != nil {
+				var zero T
+				yield(zero, pg.err)
+				return
+			}
+			pages++
+
+			if p.opts.prefetch && pg.next != "" {
+				pending = prefetch(pg.next)
+			}
+
+			for i := skip; i < len(pg.items); i++ {
+				if limited() {
+					return
+				}
+				items++
+				p.setCursor(Cursor{Token: token, Offset: i + 1})
+				if !yield(pg.items[i], nil) {
+					return
+				}
+			}
+			skip = 0
+
+			p.setCursor(Cursor{Token: pg.next})
+			if pg.next == "" {
+				return
+			}
+			token = pg.next
+		}
+	}
+}
+
+// NewVolumePager returns a new Pager that issues ListVolumes requests
+// to iterate over all available volumes. The request's starting token is
+// used unless a cursor or starting token option is provided.
+func NewVolumePager(
+	client csi.ControllerClient,
+	req *csi.ListVolumesRequest,
+	opts ...PagerOption,
+) *Pager[*csi.ListVolumesResponse_Entry] {
+	opts = append([]PagerOption{
+		WithStartingToken(req.GetStartingToken())}, opts...)
+	var p *Pager[*csi.ListVolumesResponse_Entry]
+	p = NewPager(func(
+		ctx context.Context, token string,
+	) ([]*csi.ListVolumesResponse_Entry, string, error) {
+		res, err := client.ListVolumes(ctx, &csi.ListVolumesRequest{
+			MaxEntries:    req.GetMaxEntries(),
+			StartingToken: token,
+		}, p.opts.callOpts...)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.GetEntries(), res.GetNextToken(), nil
+	}, opts...)
+	return p
+}
+
+// NewSnapshotPager returns a new Pager that issues ListSnapshots requests
+// to iterate over all available snapshots. The request's starting token
+// is used unless a cursor or starting token option is provided.
+func NewSnapshotPager(
+	client csi.ControllerClient,
+	req *csi.ListSnapshotsRequest,
+	opts ...PagerOption,
+) *Pager[*csi.ListSnapshotsResponse_Entry] {
+	opts = append([]PagerOption{
+		WithStartingToken(req.GetStartingToken())}, opts...)
+	var p *Pager[*csi.ListSnapshotsResponse_Entry]
+	p = NewPager(func(
+		ctx context.Context, token string,
+	) ([]*csi.ListSnapshotsResponse_Entry, string, error) {
+		res, err := client.ListSnapshots(ctx, &csi.ListSnapshotsRequest{
+			MaxEntries:     req.GetMaxEntries(),
+			StartingToken:  token,
+			SourceVolumeId: req.GetSourceVolumeId(),
+			SnapshotId:     req.GetSnapshotId(),
+			Secrets:        req.GetSecrets(),
+		}, p.opts.callOpts...)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.GetEntries(), res.GetNextToken(), nil
+	}, opts...)
+	return p
+}
+
+// pageChan sends the values of the provided iterator over a Go channel.
+// An error is sent over the error channel, which is closed before the
+// value channel.
+func pageChan[E, T any](
+	ctx context.Context,
+	seq iter.Seq2[E, error],
+	conv func(E) (T, bool),
+) (<-chan T, <-chan error) {
+	var (
+		cval = make(chan T)
+		cerr = make(chan error)
+	)
+	go func() {
+		defer close(cval)
+		defer close(cerr)
+		for e, err := range seq {
+			if err != nil {
+				select {
+				case cerr <- err:
+				case <-ctx.Done():
+				}
+				return
+			}
+			v, ok := conv(e)
+			if !ok {
+				continue
+			}
+			select {
+			case cval <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return cval, cerr
+}
@@ -0,0 +1,214 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package csi_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/dell/gocsi/mock/service"
+	utils "github.com/dell/gocsi/utils/csi"
+)
+
+// testPages returns a PageFunc that serves the provided pages. A page's
+// token is its index, and the first page's token is empty.
+func testPages(fetches *int32, pages ...[]int) utils.PageFunc[int] {
+	return func(_ context.Context, token string) ([]int, string, error) {
+		atomic.AddInt32(fetches, 1)
+		i := 0
+		if token != "" {
+			var err error
+			if i, err = strconv.Atoi(token); err != nil {
+				return nil, "", err
+			}
+		}
+		var next string
+		if i+1 < len(pages) {
+			next = strconv.Itoa(i + 1)
+		}
+		return pages[i], next, nil
+	}
+}
+
+func collect[T any](p *utils.Pager[T]) (items []T, err error) {
+	for v, err := range p.All(context.Background()) {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, v)
+	}
+	return items, nil
+}
+
+func TestPager(t *testing.T) {
+	RegisterTestingT(t)
+
+	var fetches int32
+	p := utils.NewPager(testPages(&fetches, []int{1, 2}, []int{}, []int{3}))
+	items, err := collect(p)
+	Expect(err).To(BeNil())
+	Expect(items).To(Equal([]int{1, 2, 3}))
+	Expect(fetches).To(Equal(int32(3)))
+	Expect(p.Cursor()).To(Equal(utils.Cursor{}))
+}
+
+func TestPagerLimit(t *testing.T) {
+	RegisterTestingT(t)
+
+	var fetches int32
+	p := utils.NewPager(
+		testPages(&fetches, []int{1, 2}, []int{3, 4}, []int{5}),
+		utils.WithLimit(2))
+	items, err := collect(p)
+	Expect(err).To(BeNil())
+	Expect(items).To(Equal([]int{1, 2}))
+
+	// The next page is not retrieved once the limit is reached.
+	Expect(fetches).To(Equal(int32(1)))
+	Expect(p.Cursor()).To(Equal(utils.Cursor{Token: "1"}))
+
+	p = utils.NewPager(
+		testPages(&fetches, []int{1, 2}, []int{3, 4}, []int{5}),
+		utils.WithLimit(3))
+	items, err = collect(p)
+	Expect(err).To(BeNil())
+	Expect(items).To(Equal([]int{1, 2, 3}))
+	Expect(p.Cursor()).To(Equal(utils.Cursor{Token: "1", Offset: 1}))
+}
+
+func TestPagerResume(t *testing.T) {
+	RegisterTestingT(t)
+
+	var fetches int32
+	fetch := testPages(&fetches, []int{1, 2}, []int{3, 4}, []int{5})
+
+	// Stop after the third item and resume from the saved cursor.
+	p := utils.NewPager(fetch)
+	var items []int
+	for v, err := range p.All(context.Background()) {
+		Expect(err).To(BeNil())
+		items = append(items, v)
+		if v == 3 {
+			break
+		}
+	}
+	Expect(items).To(Equal([]int{1, 2, 3}))
+	cursor := p.Cursor()
+	Expect(cursor).To(Equal(utils.Cursor{Token: "1", Offset: 1}))
+
+	items, err := collect(utils.NewPager(fetch, utils.WithCursor(cursor)))
+	Expect(err).To(BeNil())
+	Expect(items).To(Equal([]int{4, 5}))
+
+	items, err = collect(utils.NewPager(fetch, utils.WithStartingToken("2")))
+	Expect(err).To(BeNil())
+	Expect(items).To(Equal([]int{5}))
+}
+
+func TestPagerPrefetch(t *testing.T) {
+	RegisterTestingT(t)
+
+	var fetches int32
+	p := utils.NewPager(
+		testPages(&fetches, []int{1, 2}, []int{3}),
+		utils.WithPrefetch())
+
+	var items []int
+	for v, err := range p.All(context.Background()) {
+		Expect(err).To(BeNil())
+		items = append(items, v)
+
+		// The second page is retrieved while the first is consumed.
+		if v == 1 {
+			Eventually(func() int32 {
+				return atomic.LoadInt32(&fetches)
+			}).Should(Equal(int32(2)))
+		}
+	}
+	Expect(items).To(Equal([]int{1, 2, 3}))
+	Expect(atomic.LoadInt32(&fetches)).To(Equal(int32(2)))
+}
+
+func TestPagerError(t *testing.T) {
+	RegisterTestingT(t)
+
+	errFetch := errors.New("fetch failed")
+	p := utils.NewPager(func(_ context.Context, token string) ([]int, string, error) {
+		if token == "" {
+			return []int{1}, "1", nil
+		}
+		return nil, "", errFetch
+	})
+	items, err := collect(p)
+	Expect(err).To(Equal(errFetch))
+	Expect(items).To(Equal([]int{1}))
+
+	// A page whose next token is its own token is an error.
+	p = utils.NewPager(func(_ context.Context, _ string) ([]int, string, error) {
+		return []int{1}, "1", nil
+	})
+	items, err = collect(p)
+	Expect(err).ToNot(BeNil())
+	Expect(items).To(Equal([]int{1}))
+}
+
+func TestVolumePager(t *testing.T) {
+	RegisterTestingT(t)
+
+	svc := service.NewClient()
+	p := utils.NewVolumePager(svc, &csi.ListVolumesRequest{MaxEntries: 1},
+		utils.WithPrefetch())
+	var ids []string
+	for e, err := range p.All(context.Background()) {
+		Expect(err).To(BeNil())
+		ids = append(ids, e.GetVolume().GetVolumeId())
+	}
+	// The mock service is initialized to have 3 volumes.
+	Expect(ids).To(HaveLen(3))
+
+	// Resume from the second volume.
+	p = utils.NewVolumePager(svc,
+		&csi.ListVolumesRequest{MaxEntries: 2, StartingToken: "1"})
+	entries, err := collect(p)
+	Expect(err).To(BeNil())
+	Expect(entries).To(HaveLen(2))
+	Expect(entries[0].GetVolume().GetVolumeId()).To(Equal(ids[1]))
+}
+
+func TestSnapshotPager(t *testing.T) {
+	RegisterTestingT(t)
+
+	svc := service.NewClient()
+	ctx := context.Background()
+	for _, name := range []string{"snapshot0", "snapshot1"} {
+		_, err := svc.CreateSnapshot(ctx,
+			&csi.CreateSnapshotRequest{SourceVolumeId: "1", Name: name})
+		Expect(err).To(BeNil())
+	}
+
+	p := utils.NewSnapshotPager(svc, &csi.ListSnapshotsRequest{MaxEntries: 1},
+		utils.WithLimit(1))
+	entries, err := collect(p)
+	Expect(err).To(BeNil())
+	Expect(entries).To(HaveLen(1))
+}
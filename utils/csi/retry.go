@@ -0,0 +1,190 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package csi
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	csictx "github.com/dell/gocsi/context"
+	"github.com/dell/gocsi/utils/rpcs"
+)
+
+// RetryPolicy describes how failed calls to a method are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the
+	// first. A value less than two disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the amount of time to wait before the first
+	// retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum amount of time to wait between attempts.
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor by which the backoff grows after each
+	// retry.
+	Multiplier float64
+
+	// Jitter is the fraction, from 0 to 1, by which each backoff is
+	// randomly increased or decreased so that clients do not retry in
+	// lockstep.
+	Jitter float64
+
+	// Codes are the codes of the errors that are retried.
+	Codes []codes.Code
+}
+
+// DefaultRetryPolicy is the policy used for methods without a policy. It
+// retries the codes used by GoCSI and SPs to indicate a call may succeed
+// later: Aborted, which the serial volume access interceptor returns when
+// an operation is pending for a volume, Unavailable, and
+// ResourceExhausted.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+	Codes: []codes.Code{
+		codes.Aborted,
+		codes.Unavailable,
+		codes.ResourceExhausted,
+	},
+}
+
+// backoff returns the amount of time to wait before the provided retry,
+// starting at one.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := float64(p.InitialBackoff)
+	for i := 1; i < retry; i++ {
+		d *= max(p.Multiplier, 1)
+	}
+	if p.MaxBackoff > 0 {
+		d = min(d, float64(p.MaxBackoff))
+	}
+	if p.Jitter > 0 {
+		d *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(d)
+}
+
+// RetryOption configures the interceptor returned by NewRetryInterceptor.
+type RetryOption func(*retryOpts)
+
+type retryOpts struct {
+	policies map[string]RetryPolicy
+}
+
+// WithRetryPolicy is a RetryOption that sets the retry policy for the
+// provided method. Please see rpcs.MethodNames for the names by which a
+// method may be specified, ex. "NodePublishVolume", or rpcs.AllMethods to
+// replace the DefaultRetryPolicy.
+func WithRetryPolicy(method string, p RetryPolicy) RetryOption {
+	return func(o *retryOpts) {
+		o.policies[method] = p
+	}
+}
+
+// NewRetryInterceptor returns a new UnaryClientInterceptor that retries
+// failed calls according to per-method retry policies. The time between
+// attempts grows exponentially with random jitter, and a call is not
+// retried if its context is done or its deadline would pass before the
+// next attempt.
+//
+// CSI methods are idempotent, so a request may be safely retried as long
+// as it identifies the object it operates on. A CreateVolume or
+// CreateSnapshot request without a name is never retried because a retry
+// could create a second volume or snapshot.
+func NewRetryInterceptor(opts ...RetryOption) grpc.UnaryClientInterceptor {
+	o := retryOpts{policies: map[string]RetryPolicy{}}
+	for _, setOpt := range opts {
+		setOpt(&o)
+	}
+	return o.handle
+}
+
+// policy returns the retry policy for the provided method.
+func (o *retryOpts) policy(method string) RetryPolicy {
+	if p, ok := rpcs.LookupMethod(o.policies, method); ok {
+		return p
+	}
+	return DefaultRetryPolicy
+}
+
+// isRetrySafe returns a flag indicating whether the request may be
+// retried without creating a duplicate object.
+func isRetrySafe(req interface{}) bool {
+	m, ok := rpcs.LookupRequest(req)
+	if !ok || m.Idempotency != rpcs.IdempotencyByName {
+		return true
+	}
+	name, _ := m.ObjectName(req)
+	return name != ""
+}
+
+func (o *retryOpts) handle(
+	ctx context.Context,
+	method string,
+	req, rep interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	p := o.policy(method)
+	if p.MaxAttempts < 2 || !isRetrySafe(req) {
+		return invoker(ctx, method, req, rep, cc, opts...)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := invoker(ctx, method, req, rep, cc, opts...)
+		if err == nil || attempt >= p.MaxAttempts ||
+			!slices.Contains(p.Codes, status.Code(err)) {
+			return err
+		}
+
+		// Do not wait for a retry that cannot complete before the
+		// call's deadline.
+		d := p.backoff(attempt)
+		if dl, ok := ctx.Deadline(); ok && time.Now().Add(d).After(dl) {
+			return err
+		}
+
+		csictx.GetLogger(ctx).Debug("retrying call",
+			"method", method,
+			"attempt", attempt,
+			"backoff", d,
+			"error", err)
+
+		t := time.NewTimer(d)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		}
+	}
+}
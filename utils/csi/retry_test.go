@@ -0,0 +1,167 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package csi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	utils "github.com/dell/gocsi/utils/csi"
+)
+
+const createVolumeMethod = "/csi.v1.Controller/CreateVolume"
+
+var testRetryPolicy = utils.RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     2 * time.Millisecond,
+	Multiplier:     2,
+	Codes:          []codes.Code{codes.Aborted, codes.Unavailable},
+}
+
+// failingInvoker returns an invoker that fails with the provided codes in
+// order before it succeeds.
+func failingInvoker(n *int, errs ...codes.Code) grpc.UnaryInvoker {
+	return func(
+		_ context.Context, _ string, _, _ interface{},
+		_ *grpc.ClientConn, _ ...grpc.CallOption,
+	) error {
+		*n++
+		if *n <= len(errs) {
+			return status.Error(errs[*n-1], "failed")
+		}
+		return nil
+	}
+}
+
+func TestRetryInterceptor(t *testing.T) {
+	RegisterTestingT(t)
+
+	i := utils.NewRetryInterceptor(
+		utils.WithRetryPolicy("*", testRetryPolicy))
+	req := &csi.CreateVolumeRequest{Name: "vol"}
+
+	var n int
+	err := i(context.Background(), createVolumeMethod, req, nil, nil,
+		failingInvoker(&n, codes.Aborted, codes.Unavailable))
+	Expect(err).To(BeNil())
+	Expect(n).To(Equal(3))
+
+	// The last error is returned once the attempts are exhausted.
+	n = 0
+	err = i(context.Background(), createVolumeMethod, req, nil, nil,
+		failingInvoker(&n, codes.Aborted, codes.Aborted, codes.Unavailable))
+	Expect(status.Code(err)).To(Equal(codes.Unavailable))
+	Expect(n).To(Equal(3))
+
+	// Other codes are not retried.
+	n = 0
+	err = i(context.Background(), createVolumeMethod, req, nil, nil,
+		failingInvoker(&n, codes.InvalidArgument))
+	Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	Expect(n).To(Equal(1))
+}
+
+func TestRetryInterceptorIdempotency(t *testing.T) {
+	RegisterTestingT(t)
+
+	i := utils.NewRetryInterceptor(
+		utils.WithRetryPolicy("*", testRetryPolicy))
+
+	// A request without a name could create a duplicate if retried.
+	var n int
+	err := i(context.Background(), createVolumeMethod,
+		&csi.CreateVolumeRequest{}, nil, nil,
+		failingInvoker(&n, codes.Unavailable))
+	Expect(status.Code(err)).To(Equal(codes.Unavailable))
+	Expect(n).To(Equal(1))
+
+	n = 0
+	err = i(context.Background(), "/csi.v1.Controller/CreateSnapshot",
+		&csi.CreateSnapshotRequest{SourceVolumeId: "1"}, nil, nil,
+		failingInvoker(&n, codes.Unavailable))
+	Expect(status.Code(err)).To(Equal(codes.Unavailable))
+	Expect(n).To(Equal(1))
+
+	n = 0
+	err = i(context.Background(), "/csi.v1.Controller/CreateSnapshot",
+		&csi.CreateSnapshotRequest{SourceVolumeId: "1", Name: "snap"},
+		nil, nil, failingInvoker(&n, codes.Unavailable))
+	Expect(err).To(BeNil())
+	Expect(n).To(Equal(2))
+}
+
+func TestRetryInterceptorMethodPolicy(t *testing.T) {
+	RegisterTestingT(t)
+
+	noRetries := testRetryPolicy
+	noRetries.MaxAttempts = 1
+	i := utils.NewRetryInterceptor(
+		utils.WithRetryPolicy("*", testRetryPolicy),
+		utils.WithRetryPolicy("Controller/CreateVolume", noRetries))
+
+	var n int
+	err := i(context.Background(), createVolumeMethod,
+		&csi.CreateVolumeRequest{Name: "vol"}, nil, nil,
+		failingInvoker(&n, codes.Aborted))
+	Expect(status.Code(err)).To(Equal(codes.Aborted))
+	Expect(n).To(Equal(1))
+
+	n = 0
+	err = i(context.Background(), "/csi.v1.Node/NodeStageVolume",
+		&csi.NodeStageVolumeRequest{VolumeId: "1"}, nil, nil,
+		failingInvoker(&n, codes.Aborted))
+	Expect(err).To(BeNil())
+	Expect(n).To(Equal(2))
+}
+
+func TestRetryInterceptorDeadline(t *testing.T) {
+	RegisterTestingT(t)
+
+	slow := testRetryPolicy
+	slow.InitialBackoff = time.Hour
+	slow.MaxBackoff = time.Hour
+	i := utils.NewRetryInterceptor(utils.WithRetryPolicy("*", slow))
+
+	// A retry that cannot begin before the deadline is not attempted.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	var n int
+	start := time.Now()
+	err := i(ctx, createVolumeMethod, &csi.CreateVolumeRequest{Name: "vol"},
+		nil, nil, failingInvoker(&n, codes.Aborted))
+	Expect(status.Code(err)).To(Equal(codes.Aborted))
+	Expect(n).To(Equal(1))
+	Expect(time.Since(start)).To(BeNumerically("<", time.Minute))
+
+	// A cancelled context stops the wait between attempts.
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	n = 0
+	err = i(ctx, createVolumeMethod, &csi.CreateVolumeRequest{Name: "vol"},
+		nil, nil, failingInvoker(&n, codes.Aborted))
+	Expect(status.Code(err)).To(Equal(codes.Aborted))
+	Expect(n).To(Equal(1))
+}
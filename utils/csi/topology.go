@@ -0,0 +1,264 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package csi
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// topologyNameRX matches a topology key name or segment value, which
+	// the CSI specification requires to be 63 characters or less, begin and
+	// end with an alphanumeric character, and contain only dashes,
+	// underscores, dots, or alphanumerics in between.
+	topologyNameRX = regexp.MustCompile(
+		`^[a-zA-Z0-9](?:[-_.a-zA-Z0-9]{0,61}[a-zA-Z0-9])?$`)
+
+	// topologyPrefixRX matches a topology key prefix, which the CSI
+	// specification requires to be 63 characters or less, begin and end
+	// with a lower-case alphanumeric character, and contain only dashes,
+	// dots, or lower-case alphanumerics in between.
+	topologyPrefixRX = regexp.MustCompile(
+		`^[a-z0-9](?:[-.a-z0-9]{0,61}[a-z0-9])?$`)
+)
+
+// ParseTopology parses a topology from a string of comma-separated
+// segments, ex. "region=R1, zone=Z2". The topology is validated with
+// ValidateTopology.
+func ParseTopology(s string) (*csi.Topology, error) {
+	segs := map[string]string{}
+	for _, seg := range strings.Split(s, ",") {
+		if seg = strings.TrimSpace(seg); seg == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(seg, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid topology segment %q", seg)
+		}
+		segs[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	t := &csi.Topology{Segments: segs}
+	if err := ValidateTopology(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// ValidateTopology returns an error if the provided topology does not have
+// at least one segment or its keys and values do not follow the rules of
+// the CSI specification.
+func ValidateTopology(t *csi.Topology) error {
+	if len(t.GetSegments()) == 0 {
+		return errors.New("topology has no segments")
+	}
+
+	var (
+		prefixes = map[string]struct{}{}
+		keys     = map[string]string{}
+	)
+	for _, k := range slices.Sorted(maps.Keys(t.Segments)) {
+		name := k
+		if prefix, n, ok := strings.Cut(k, "/"); ok {
+			if !topologyPrefixRX.MatchString(prefix) {
+				return fmt.Errorf("invalid topology key prefix %q", k)
+			}
+			prefixes[prefix] = struct{}{}
+			name = n
+		} else {
+			prefixes[""] = struct{}{}
+		}
+		if !topologyNameRX.MatchString(name) {
+			return fmt.Errorf("invalid topology key %q", k)
+		}
+		if v := t.Segments[k]; !topologyNameRX.MatchString(v) {
+			return fmt.Errorf("invalid topology value %q for key %q", v, k)
+		}
+
+		// Keys are case-insensitive.
+		lk := strings.ToLower(k)
+		if other, ok := keys[lk]; ok {
+			return fmt.Errorf("duplicate topology keys %q and %q", other, k)
+		}
+		keys[lk] = k
+	}
+
+	// If a key prefix is specified it must be the same for all keys.
+	if len(prefixes) > 1 {
+		return fmt.Errorf("topology keys have different prefixes: %s",
+			topologyString(t))
+	}
+	return nil
+}
+
+// ValidateTopologyRequirement returns an error if the provided topology
+// requirement does not follow the rules of the CSI specification: either
+// the requisite or preferred topologies must be specified, all topologies
+// must be valid, and if the requisite topologies are specified then every
+// preferred topology must also be requisite. A nil requirement is valid.
+func ValidateTopologyRequirement(r *csi.TopologyRequirement) error {
+	if r == nil {
+		return nil
+	}
+	if len(r.Requisite) == 0 && len(r.Preferred) == 0 {
+		return errors.New(
+			"topology requirement has no requisite or preferred topologies")
+	}
+	for _, t := range r.Requisite {
+		if err := ValidateTopology(t); err != nil {
+			return fmt.Errorf("requisite: %w", err)
+		}
+	}
+	for _, t := range r.Preferred {
+		if err := ValidateTopology(t); err != nil {
+			return fmt.Errorf("preferred: %w", err)
+		}
+		if len(r.Requisite) > 0 && !containsTopology(r.Requisite, t) {
+			return fmt.Errorf("preferred topology is not requisite: %s",
+				topologyString(t))
+		}
+	}
+	return nil
+}
+
+func containsTopology(topologies []*csi.Topology, t *csi.Topology) bool {
+	return slices.ContainsFunc(topologies, func(v *csi.Topology) bool {
+		return EqualTopology(v, t)
+	})
+}
+
+// MatchTopology returns a flag indicating whether every segment of the
+// topology "t" is also a segment of "segments", ex. {"zone": "Z1"} matches
+// a node with the topology {"region": "R1", "zone": "Z1"}. Keys are
+// compared without regard to case.
+func MatchTopology(t, segments *csi.Topology) bool {
+	if t == nil || segments == nil {
+		return false
+	}
+	for k, v := range t.Segments {
+		if !hasSegment(segments.Segments, k, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasSegment returns a flag indicating whether the provided segments have
+// the key, compared without regard to case, with the provided value.
+func hasSegment(segments map[string]string, key, value string) bool {
+	if v, ok := segments[key]; ok {
+		return v == value
+	}
+	for k, v := range segments {
+		if strings.EqualFold(k, key) {
+			return v == value
+		}
+	}
+	return false
+}
+
+// IntersectTopologies returns the topologies in "a" that match at least one
+// topology in "b", ex. the requisite topologies that match a node's
+// accessible topology. The order of "a" is preserved and duplicates are
+// removed.
+func IntersectTopologies(a, b []*csi.Topology) []*csi.Topology {
+	var out []*csi.Topology
+	for _, t := range a {
+		if containsTopology(out, t) {
+			continue
+		}
+		if slices.ContainsFunc(b, func(v *csi.Topology) bool {
+			return MatchTopology(t, v)
+		}) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// RankTopologies returns the topologies of the provided requirement in the
+// order an SP should attempt to provision a volume: the preferred
+// topologies in their order, followed by the remaining requisite
+// topologies in their order. Duplicates are removed.
+func RankTopologies(r *csi.TopologyRequirement) []*csi.Topology {
+	var out []*csi.Topology
+	for _, t := range slices.Concat(r.GetPreferred(), r.GetRequisite()) {
+		if !containsTopology(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// SelectTopologies returns the "n" topologies from which a new volume
+// should be accessible according to the provided requirement, ex. two
+// zones for a synchronously replicated volume. The result may be used as
+// the volume's accessible topology in a CreateVolume response.
+//
+// The "available" topologies are those in which the SP is able to
+// provision the volume; a nil list indicates the SP may provision the
+// volume anywhere. The topologies are selected in the order returned by
+// RankTopologies, skipping those that do not match an available topology.
+// If the requirement has no requisite topologies then the available
+// topologies are used once the preferred topologies are exhausted. If the
+// requirement is nil then the first "n" available topologies are returned.
+//
+// An InvalidArgument error is returned if the requirement is invalid and a
+// ResourceExhausted error is returned if fewer than "n" topologies satisfy
+// the requirement.
+func SelectTopologies(
+	r *csi.TopologyRequirement, available []*csi.Topology, n int,
+) ([]*csi.Topology, error) {
+	if err := ValidateTopologyRequirement(r); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if r == nil && available == nil {
+		return nil, nil
+	}
+	n = max(n, 1)
+
+	candidates := RankTopologies(r)
+	if available != nil {
+		candidates = IntersectTopologies(candidates, available)
+	}
+	if len(r.GetRequisite()) == 0 {
+		for _, t := range available {
+			if !slices.ContainsFunc(candidates, func(v *csi.Topology) bool {
+				return MatchTopology(v, t)
+			}) {
+				candidates = append(candidates, t)
+			}
+		}
+	}
+
+	if len(candidates) < n {
+		return nil, status.Errorf(codes.ResourceExhausted,
+			"volume cannot be accessible from %d topologies: "+
+				"%d satisfy the topology requirement", n, len(candidates))
+	}
+	return candidates[:n], nil
+}
@@ -0,0 +1,252 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package csi_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	utils "github.com/dell/gocsi/utils/csi"
+)
+
+// zones returns a topology in region R1 for each of the provided zones.
+func zones(names ...string) []*csi.Topology {
+	topologies := make([]*csi.Topology, len(names))
+	for i, z := range names {
+		topologies[i] = &csi.Topology{
+			Segments: map[string]string{"region": "R1", "zone": z},
+		}
+	}
+	return topologies
+}
+
+// zoneNames returns the zone of each of the provided topologies.
+func zoneNames(topologies []*csi.Topology) []string {
+	names := make([]string, len(topologies))
+	for i, t := range topologies {
+		names[i] = t.Segments["zone"]
+	}
+	return names
+}
+
+func TestParseTopology(t *testing.T) {
+	RegisterTestingT(t)
+
+	topology, err := utils.ParseTopology("region=R1, zone=Z2")
+	Expect(err).To(BeNil())
+	Expect(topology.Segments).To(Equal(
+		map[string]string{"region": "R1", "zone": "Z2"}))
+
+	topology, err = utils.ParseTopology(
+		"example.com/region=R1,example.com/zone=Z2")
+	Expect(err).To(BeNil())
+	Expect(topology.Segments).To(HaveLen(2))
+
+	for _, s := range []string{
+		"",
+		"zone",
+		"zone=",
+		"zone=-Z2",
+		"-zone=Z2",
+		"zone=Z2,Zone=Z3",
+		"Example.com/zone=Z2",
+		"example.com/zone=Z2,region=R1",
+		"example.com/zone=Z2,example.org/region=R1",
+		"zone=" + strings.Repeat("z", 64),
+	} {
+		_, err := utils.ParseTopology(s)
+		Expect(err).ToNot(BeNil(), s)
+	}
+
+	_, err = utils.ParseTopology("zone=" + strings.Repeat("z", 63))
+	Expect(err).To(BeNil())
+}
+
+func TestValidateTopologyRequirement(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(utils.ValidateTopologyRequirement(nil)).To(BeNil())
+	Expect(utils.ValidateTopologyRequirement(
+		&csi.TopologyRequirement{})).ToNot(BeNil())
+	Expect(utils.ValidateTopologyRequirement(&csi.TopologyRequirement{
+		Preferred: zones("Z1"),
+	})).To(BeNil())
+
+	// All preferred topologies must be requisite.
+	Expect(utils.ValidateTopologyRequirement(&csi.TopologyRequirement{
+		Requisite: zones("Z2", "Z3"),
+		Preferred: zones("Z3"),
+	})).To(BeNil())
+	Expect(utils.ValidateTopologyRequirement(&csi.TopologyRequirement{
+		Requisite: zones("Z2", "Z3"),
+		Preferred: zones("Z4"),
+	})).ToNot(BeNil())
+
+	Expect(utils.ValidateTopologyRequirement(&csi.TopologyRequirement{
+		Requisite: []*csi.Topology{{}},
+	})).ToNot(BeNil())
+}
+
+func TestMatchTopology(t *testing.T) {
+	RegisterTestingT(t)
+
+	node := &csi.Topology{
+		Segments: map[string]string{"region": "R1", "zone": "Z1", "rack": "r1"},
+	}
+	Expect(utils.MatchTopology(zones("Z1")[0], node)).To(BeTrue())
+	Expect(utils.MatchTopology(zones("Z2")[0], node)).To(BeFalse())
+	Expect(utils.MatchTopology(&csi.Topology{
+		Segments: map[string]string{"Zone": "Z1"},
+	}, node)).To(BeTrue())
+	Expect(utils.MatchTopology(node, zones("Z1")[0])).To(BeFalse())
+	Expect(utils.MatchTopology(nil, node)).To(BeFalse())
+
+	Expect(zoneNames(utils.IntersectTopologies(
+		zones("Z1", "Z2", "Z3", "Z1"), zones("Z3", "Z1")),
+	)).To(Equal([]string{"Z1", "Z3"}))
+	Expect(utils.IntersectTopologies(zones("Z1"), nil)).To(BeEmpty())
+}
+
+func TestRankTopologies(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(utils.RankTopologies(nil)).To(BeEmpty())
+	Expect(zoneNames(utils.RankTopologies(&csi.TopologyRequirement{
+		Requisite: zones("Z2", "Z3", "Z4", "Z5"),
+		Preferred: zones("Z4", "Z2"),
+	}))).To(Equal([]string{"Z4", "Z2", "Z3", "Z5"}))
+}
+
+// The following tests are the examples of the TopologyRequirement message
+// in the CSI specification.
+
+func TestSelectTopologiesRequisiteExamples(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Example 1: the volume MUST be accessible from zone Z2.
+	r := &csi.TopologyRequirement{Requisite: zones("Z2")}
+	out, err := utils.SelectTopologies(r, nil, 1)
+	Expect(err).To(BeNil())
+	Expect(zoneNames(out)).To(Equal([]string{"Z2"}))
+	_, err = utils.SelectTopologies(r, zones("Z3"), 1)
+	Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+
+	// Example 2: the volume MUST be accessible from zone Z2 or Z3.
+	r = &csi.TopologyRequirement{Requisite: zones("Z2", "Z3")}
+	out, err = utils.SelectTopologies(r, zones("Z3", "Z4"), 1)
+	Expect(err).To(BeNil())
+	Expect(zoneNames(out)).To(Equal([]string{"Z3"}))
+
+	// Example 3: the volume MUST be accessible from any combination of
+	// two unique zones of Z2, Z3, and Z4.
+	r = &csi.TopologyRequirement{Requisite: zones("Z2", "Z3", "Z4")}
+	out, err = utils.SelectTopologies(r, zones("Z4", "Z2"), 2)
+	Expect(err).To(BeNil())
+	Expect(zoneNames(out)).To(Equal([]string{"Z2", "Z4"}))
+	_, err = utils.SelectTopologies(r, zones("Z4", "Z5"), 2)
+	Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+}
+
+func TestSelectTopologiesPreferredExamples(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Example 1: attempt Z3 and fall back to Z2.
+	r := &csi.TopologyRequirement{
+		Requisite: zones("Z2", "Z3"),
+		Preferred: zones("Z3"),
+	}
+	out, err := utils.SelectTopologies(r, nil, 1)
+	Expect(err).To(BeNil())
+	Expect(zoneNames(out)).To(Equal([]string{"Z3"}))
+	out, err = utils.SelectTopologies(r, zones("Z2"), 1)
+	Expect(err).To(BeNil())
+	Expect(zoneNames(out)).To(Equal([]string{"Z2"}))
+
+	// Example 2: attempt Z4, fall back to Z2, and then to Z3 or Z5.
+	r = &csi.TopologyRequirement{
+		Requisite: zones("Z2", "Z3", "Z4", "Z5"),
+		Preferred: zones("Z4", "Z2"),
+	}
+	for _, c := range []struct {
+		available []string
+		want      string
+	}{
+		{[]string{"Z2", "Z3", "Z4", "Z5"}, "Z4"},
+		{[]string{"Z2", "Z3", "Z5"}, "Z2"},
+		{[]string{"Z5", "Z3"}, "Z3"},
+		{[]string{"Z5"}, "Z5"},
+	} {
+		out, err = utils.SelectTopologies(r, zones(c.available...), 1)
+		Expect(err).To(BeNil())
+		Expect(zoneNames(out)).To(Equal([]string{c.want}), c.available)
+	}
+
+	// Example 3: attempt Z5 and Z3, then Z5 and another requisite zone,
+	// then Z3 and another requisite zone, then any two requisite zones.
+	r = &csi.TopologyRequirement{
+		Requisite: zones("Z2", "Z3", "Z4", "Z5"),
+		Preferred: zones("Z5", "Z3"),
+	}
+	for _, c := range []struct {
+		available []string
+		want      []string
+	}{
+		{[]string{"Z2", "Z3", "Z4", "Z5"}, []string{"Z5", "Z3"}},
+		{[]string{"Z2", "Z4", "Z5"}, []string{"Z5", "Z2"}},
+		{[]string{"Z2", "Z3", "Z4"}, []string{"Z3", "Z2"}},
+		{[]string{"Z4", "Z2"}, []string{"Z2", "Z4"}},
+	} {
+		out, err = utils.SelectTopologies(r, zones(c.available...), 2)
+		Expect(err).To(BeNil())
+		Expect(zoneNames(out)).To(Equal(c.want), c.available)
+	}
+	_, err = utils.SelectTopologies(r, zones("Z4"), 2)
+	Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+}
+
+func TestSelectTopologiesWithoutRequisite(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Without requisite topologies the SP may fall back to any topology.
+	r := &csi.TopologyRequirement{Preferred: zones("Z1")}
+	out, err := utils.SelectTopologies(r, zones("Z2", "Z1"), 1)
+	Expect(err).To(BeNil())
+	Expect(zoneNames(out)).To(Equal([]string{"Z1"}))
+	out, err = utils.SelectTopologies(r, zones("Z2", "Z3"), 1)
+	Expect(err).To(BeNil())
+	Expect(zoneNames(out)).To(Equal([]string{"Z2"}))
+	out, err = utils.SelectTopologies(r, zones("Z2", "Z1"), 2)
+	Expect(err).To(BeNil())
+	Expect(zoneNames(out)).To(Equal([]string{"Z1", "Z2"}))
+
+	// Without a requirement the available topologies are used.
+	out, err = utils.SelectTopologies(nil, zones("Z2", "Z3"), 1)
+	Expect(err).To(BeNil())
+	Expect(zoneNames(out)).To(Equal([]string{"Z2"}))
+	out, err = utils.SelectTopologies(nil, nil, 1)
+	Expect(err).To(BeNil())
+	Expect(out).To(BeNil())
+
+	_, err = utils.SelectTopologies(&csi.TopologyRequirement{}, nil, 1)
+	Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+}
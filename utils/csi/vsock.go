@@ -0,0 +1,88 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package csi
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// VsockCIDAny is the context ID used to listen on all of a host's
+	// context IDs. It is specified as "any" in a vsock network address.
+	VsockCIDAny uint32 = math.MaxUint32
+
+	// VsockPortAny is the port used to listen on an unused port. It is
+	// specified as "any" in a vsock network address.
+	VsockPortAny uint32 = math.MaxUint32
+)
+
+// VsockAddr is the address of a VM socket endpoint.
+type VsockAddr struct {
+	// CID is the context ID of the VM or host.
+	CID uint32
+
+	// Port is the port number.
+	Port uint32
+}
+
+// Network returns the address's network name, "vsock".
+func (a *VsockAddr) Network() string {
+	return "vsock"
+}
+
+// String returns the address in the form "cid:port".
+func (a *VsockAddr) String() string {
+	return formatVsockID(a.CID) + ":" + formatVsockID(a.Port)
+}
+
+func formatVsockID(v uint32) string {
+	if v == math.MaxUint32 {
+		return "any"
+	}
+	return strconv.FormatUint(uint64(v), 10)
+}
+
+// ParseVsockAddr parses a vsock network address in the form "cid:port",
+// ex. "3:10000". The CID and port may be "any".
+func ParseVsockAddr(addr string) (*VsockAddr, error) {
+	c, p, ok := strings.Cut(addr, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid vsock address: %s", addr)
+	}
+	cid, err := parseVsockID(c)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vsock cid: %s: %v", addr, err)
+	}
+	port, err := parseVsockID(p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vsock port: %s: %v", addr, err)
+	}
+	return &VsockAddr{CID: cid, Port: port}, nil
+}
+
+func parseVsockID(s string) (uint32, error) {
+	if strings.EqualFold(s, "any") {
+		return math.MaxUint32, nil
+	}
+	v, err := strconv.ParseUint(s, 10, 32)
+	return uint32(v), err
+}
@@ -0,0 +1,224 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package csi
+
+import (
+	"context"
+	"net"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func listenVsock(addr string) (net.Listener, error) {
+	a, err := ParseVsockAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	f, err := newVsockFile()
+	if err != nil {
+		return nil, opError("listen", a, err)
+	}
+	if err := control(f, func(fd int) error {
+		sa := &unix.SockaddrVM{CID: a.CID, Port: a.Port}
+		if err := unix.Bind(fd, sa); err != nil {
+			return os.NewSyscallError("bind", err)
+		}
+		if err := unix.Listen(fd, unix.SOMAXCONN); err != nil {
+			return os.NewSyscallError("listen", err)
+		}
+
+		// Get the bound address in case the port was "any".
+		if sa, err := unix.Getsockname(fd); err == nil {
+			a = vsockAddr(sa)
+		}
+		return nil
+	}); err != nil {
+		f.Close()
+		return nil, opError("listen", a, err)
+	}
+	return &vsockListener{f: f, addr: a}, nil
+}
+
+func dialVsock(ctx context.Context, addr string) (net.Conn, error) {
+	a, err := ParseVsockAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	f, err := newVsockFile()
+	if err != nil {
+		return nil, opError("dial", a, err)
+	}
+	if err := connectVsock(ctx, f, a); err != nil {
+		f.Close()
+		return nil, opError("dial", a, err)
+	}
+	local := &VsockAddr{}
+	_ = control(f, func(fd int) error {
+		sa, err := unix.Getsockname(fd)
+		if err == nil {
+			local = vsockAddr(sa)
+		}
+		return err
+	})
+	return &vsockConn{File: f, local: local, remote: a}, nil
+}
+
+// newVsockFile returns a new, non-blocking vsock socket as a file so that
+// its I/O uses the runtime's network poller.
+func newVsockFile() (*os.File, error) {
+	fd, err := unix.Socket(unix.AF_VSOCK,
+		unix.SOCK_STREAM|unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+	return os.NewFile(uintptr(fd), "vsock"), nil
+}
+
+// connectVsock connects the socket to the provided address. The connection
+// is abandoned when the context is done.
+func connectVsock(ctx context.Context, f *os.File, a *VsockAddr) error {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	cerr := control(f, func(fd int) error {
+		return unix.Connect(fd, &unix.SockaddrVM{CID: a.CID, Port: a.Port})
+	})
+	if cerr != unix.EINPROGRESS {
+		return os.NewSyscallError("connect", cerr)
+	}
+
+	// Wait for the socket to become writable, which indicates the
+	// connection completed, and then get the connection's result.
+	if dl, ok := ctx.Deadline(); ok {
+		_ = f.SetWriteDeadline(dl)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = f.SetWriteDeadline(time.Unix(1, 0))
+	})
+	defer func() {
+		stop()
+		_ = f.SetWriteDeadline(time.Time{})
+	}()
+
+	waited := false
+	if err := rc.Write(func(fd uintptr) bool {
+		if !waited {
+			waited = true
+			return false
+		}
+		var n int
+		n, cerr = unix.GetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_ERROR)
+		if cerr == nil && n != 0 {
+			cerr = unix.Errno(n)
+		}
+		return true
+	}); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return os.NewSyscallError("connect", cerr)
+}
+
+// control invokes fn with the socket's file descriptor. Unlike the file's
+// Fd method, it does not put the socket into blocking mode.
+func control(f *os.File, fn func(fd int) error) error {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var ferr error
+	if err := rc.Control(func(fd uintptr) {
+		ferr = fn(int(fd))
+	}); err != nil {
+		return err
+	}
+	return ferr
+}
+
+func vsockAddr(sa unix.Sockaddr) *VsockAddr {
+	if vm, ok := sa.(*unix.SockaddrVM); ok {
+		return &VsockAddr{CID: vm.CID, Port: vm.Port}
+	}
+	return &VsockAddr{}
+}
+
+func opError(op string, a *VsockAddr, err error) error {
+	return &net.OpError{Op: op, Net: "vsock", Addr: a, Err: err}
+}
+
+type vsockListener struct {
+	f    *os.File
+	addr *VsockAddr
+}
+
+func (l *vsockListener) Accept() (net.Conn, error) {
+	rc, err := l.f.SyscallConn()
+	if err != nil {
+		return nil, opError("accept", l.addr, err)
+	}
+	var (
+		nfd  int
+		sa   unix.Sockaddr
+		aerr error
+	)
+	if err := rc.Read(func(fd uintptr) bool {
+		nfd, sa, aerr = unix.Accept4(int(fd),
+			unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC)
+		return aerr != unix.EAGAIN
+	}); err != nil {
+		return nil, opError("accept", l.addr, err)
+	}
+	if aerr != nil {
+		return nil, opError("accept", l.addr, os.NewSyscallError("accept", aerr))
+	}
+	return &vsockConn{
+		File:   os.NewFile(uintptr(nfd), "vsock"),
+		local:  l.addr,
+		remote: vsockAddr(sa),
+	}, nil
+}
+
+func (l *vsockListener) Close() error {
+	return l.f.Close()
+}
+
+func (l *vsockListener) Addr() net.Addr {
+	return l.addr
+}
+
+// vsockConn is a net.Conn for a connected vsock socket. The socket's file
+// provides the reads, writes, and deadlines.
+type vsockConn struct {
+	*os.File
+	local  *VsockAddr
+	remote *VsockAddr
+}
+
+func (c *vsockConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c *vsockConn) RemoteAddr() net.Addr {
+	return c.remote
+}
@@ -0,0 +1,121 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package middleware
+
+import (
+	"strings"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/dell/gocsi/utils/rpcs"
+)
+
+// Predicate reports whether an interceptor applies to a request.
+type Predicate func(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+) bool
+
+// When returns an interceptor that invokes i for the requests for which
+// the predicate returns true. The other requests are passed to the next
+// handler in the chain.
+func When(
+	pred Predicate,
+	i grpc.UnaryServerInterceptor,
+) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if !pred(ctx, req, info) {
+			return handler(ctx, req)
+		}
+		return i(ctx, req, info, handler)
+	}
+}
+
+// ForMethods returns an interceptor that invokes i only for the provided
+// methods. Please see rpcs.MethodNames for the names by which a method may
+// be specified, ex. "NodePublishVolume" or rpcs.AllMethods.
+func ForMethods(
+	methods []string,
+	i grpc.UnaryServerInterceptor,
+) grpc.UnaryServerInterceptor {
+	return When(func(
+		_ context.Context, _ interface{}, info *grpc.UnaryServerInfo,
+	) bool {
+		return rpcs.MatchMethod(methods, info.FullMethod)
+	}, i)
+}
+
+// ExceptMethods returns an interceptor that invokes i for all methods
+// other than the provided methods.
+func ExceptMethods(
+	methods []string,
+	i grpc.UnaryServerInterceptor,
+) grpc.UnaryServerInterceptor {
+	return When(func(
+		_ context.Context, _ interface{}, info *grpc.UnaryServerInfo,
+	) bool {
+		return !rpcs.MatchMethod(methods, info.FullMethod)
+	}, i)
+}
+
+// ForServices returns an interceptor that invokes i only for the methods
+// of the provided services. A service may be the full service name, ex.
+// "csi.v1.Node", or the service alone, ex. "Node".
+func ForServices(
+	services []string,
+	i grpc.UnaryServerInterceptor,
+) grpc.UnaryServerInterceptor {
+	set := make(map[string]struct{}, len(services))
+	for _, s := range services {
+		set[s] = struct{}{}
+	}
+	return When(func(
+		_ context.Context, _ interface{}, info *grpc.UnaryServerInfo,
+	) bool {
+		for _, s := range serviceNames(info.FullMethod) {
+			if _, ok := set[s]; ok {
+				return true
+			}
+		}
+		return false
+	}, i)
+}
+
+// serviceNames returns the names by which the service of a gRPC method
+// may be referenced: the full service name, ex. "csi.v1.Node", and the
+// service alone, ex. "Node".
+func serviceNames(fullMethod string) []string {
+	i := strings.LastIndex(fullMethod, "/")
+	if i < 0 {
+		return nil
+	}
+	full := strings.TrimPrefix(fullMethod[:i], "/")
+	names := []string{full}
+	if j := strings.LastIndex(full, "."); j >= 0 {
+		names = append(names, full[j+1:])
+	}
+	return names
+}
@@ -0,0 +1,144 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package middleware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/dell/gocsi/utils/middleware"
+	"github.com/dell/gocsi/utils/rpcs"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// invoked returns a flag indicating whether the interceptor is invoked
+// for the provided method.
+func invoked(
+	wrap func(grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor,
+	fullMethod string,
+) bool {
+	var called bool
+	i := wrap(func(
+		ctx context.Context,
+		req interface{},
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		called = true
+		return handler(ctx, req)
+	})
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	rep, _ := i(context.Background(), "request",
+		&grpc.UnaryServerInfo{FullMethod: fullMethod}, handler)
+	if rep != "response" {
+		panic("handler not invoked")
+	}
+	return called
+}
+
+func TestForMethods(t *testing.T) {
+	forPublish := func(i grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+		return middleware.ForMethods([]string{
+			"NodePublishVolume",
+			"Controller/ControllerPublishVolume",
+			"/csi.v1.Identity/Probe",
+		}, i)
+	}
+	assert.True(t, invoked(forPublish, "/csi.v1.Node/NodePublishVolume"))
+	assert.True(t, invoked(forPublish, "/csi.v1.Controller/ControllerPublishVolume"))
+	assert.True(t, invoked(forPublish, "/csi.v1.Identity/Probe"))
+	assert.False(t, invoked(forPublish, "/csi.v1.Node/NodeUnpublishVolume"))
+
+	all := func(i grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+		return middleware.ForMethods([]string{rpcs.AllMethods}, i)
+	}
+	assert.True(t, invoked(all, "/csi.v1.Node/NodeGetInfo"))
+	assert.True(t, invoked(all, "Custom"))
+}
+
+func TestExceptMethods(t *testing.T) {
+	exceptProbe := func(i grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+		return middleware.ExceptMethods([]string{"Probe"}, i)
+	}
+	assert.False(t, invoked(exceptProbe, "/csi.v1.Identity/Probe"))
+	assert.True(t, invoked(exceptProbe, "/csi.v1.Identity/GetPluginInfo"))
+
+	none := func(i grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+		return middleware.ExceptMethods([]string{rpcs.AllMethods}, i)
+	}
+	assert.False(t, invoked(none, "/csi.v1.Node/NodeGetInfo"))
+}
+
+func TestForServices(t *testing.T) {
+	forNode := func(i grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+		return middleware.ForServices([]string{"Node", "csi.v1.Identity"}, i)
+	}
+	assert.True(t, invoked(forNode, "/csi.v1.Node/NodePublishVolume"))
+	assert.True(t, invoked(forNode, "/csi.v1.Identity/Probe"))
+	assert.False(t, invoked(forNode, "/csi.v1.Controller/CreateVolume"))
+	assert.False(t, invoked(forNode, "NodePublishVolume"))
+}
+
+func TestWhen(t *testing.T) {
+	var called bool
+	i := middleware.When(func(
+		_ context.Context, req interface{}, _ *grpc.UnaryServerInfo,
+	) bool {
+		_, ok := req.(*csi.CreateVolumeRequest)
+		return ok
+	}, func(
+		ctx context.Context,
+		req interface{},
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		called = true
+		return handler(ctx, req)
+	})
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{}
+
+	rep, err := i(context.Background(), &csi.DeleteVolumeRequest{}, info, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "response", rep)
+	assert.False(t, called)
+
+	rep, err = i(context.Background(), &csi.CreateVolumeRequest{}, info, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "response", rep)
+	assert.True(t, called)
+
+	// The combinators compose with each other and with a chain.
+	called = false
+	chain := middleware.ChainUnaryServer(
+		middleware.ForServices([]string{"Controller"},
+			middleware.ExceptMethods([]string{"DeleteVolume"}, i)))
+	_, err = chain(context.Background(), &csi.CreateVolumeRequest{},
+		&grpc.UnaryServerInfo{FullMethod: "/csi.v1.Controller/CreateVolume"},
+		handler)
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
@@ -0,0 +1,458 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package rpcs
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// IdempotencyClass describes why retrying a CSI RPC is safe.
+type IdempotencyClass int
+
+const (
+	// IdempotencyReadOnly is the class of RPCs that do not change the
+	// state of the SP, ex. ListVolumes.
+	IdempotencyReadOnly IdempotencyClass = iota
+
+	// IdempotencyByName is the class of RPCs that create an object
+	// identified by the request's name, ex. CreateVolume. A retry with
+	// the same name returns the object created by the first request.
+	IdempotencyByName
+
+	// IdempotencyByID is the class of RPCs that change the state of an
+	// existing object identified by ID, ex. NodePublishVolume. A retry
+	// succeeds if the object is already in the requested state.
+	IdempotencyByID
+)
+
+func (c IdempotencyClass) String() string {
+	switch c {
+	case IdempotencyReadOnly:
+		return "readOnly"
+	case IdempotencyByName:
+		return "byName"
+	case IdempotencyByID:
+		return "byID"
+	}
+	return fmt.Sprintf("IdempotencyClass(%d)", int(c))
+}
+
+// Method describes a CSI RPC. The descriptors are generated from the
+// protobuf descriptors of the CSI specification.
+type Method struct {
+	// FullMethod is the gRPC method, ex. "/csi.v1.Node/NodePublishVolume".
+	FullMethod string
+
+	// Service is the name of the service to which the method belongs,
+	// ex. "Node".
+	Service string
+
+	// Name is the method's name, ex. "NodePublishVolume".
+	Name string
+
+	// Mutating indicates whether the RPC may change the state of the SP.
+	// RPCs whose names, less any "Controller" or "Node" prefix, begin with
+	// "Get", "List", "Validate", or "Probe" do not.
+	Mutating bool
+
+	// Idempotency is the RPC's idempotency class.
+	Idempotency IdempotencyClass
+
+	// Creates is the kind of object the RPC creates, ex. "volume" for
+	// CreateVolume, from the name of the response's field for the object.
+	// It is empty if the RPC does not create an object.
+	Creates string
+
+	// Deletes is the kind of object the RPC deletes, ex. "volume" for
+	// DeleteVolume, from the name of the request's field for the object's
+	// ID. It is empty if the RPC does not delete an object.
+	Deletes string
+
+	// SecretsField is the name of the request's field that is marked as
+	// secret by the CSI specification, ex. "secrets". It is empty if the
+	// request has no secrets.
+	SecretsField string
+
+	// Serialized indicates whether calls to the RPC are serialized by the
+	// serial volume access middleware. The RPCs are CreateVolume, which
+	// is serialized by the volume's name, and DeleteVolume,
+	// ControllerPublishVolume, ControllerUnpublishVolume,
+	// NodePublishVolume, and NodeUnpublishVolume, which are serialized by
+	// the volume's ID.
+	Serialized bool
+
+	// Cached indicates whether the successful responses of the RPC are
+	// returned to retries by the idempotency middleware. The RPCs are
+	// CreateVolume, CreateSnapshot, and ControllerPublishVolume.
+	Cached bool
+
+	// Invalidates is the full methods of the cached RPCs whose responses
+	// are invalidated by a successful call to the RPC, ex. CreateVolume
+	// and ControllerPublishVolume for DeleteVolume.
+	Invalidates []string
+
+	request   protoreflect.MessageType
+	response  protoreflect.MessageType
+	volumeID  protoreflect.FieldDescriptor
+	nodeID    protoreflect.FieldDescriptor
+	name      protoreflect.FieldDescriptor
+	secrets   protoreflect.FieldDescriptor
+	sourceID  protoreflect.FieldDescriptor
+	deletedID protoreflect.FieldDescriptor
+	created   protoreflect.FieldDescriptor
+	createdID protoreflect.FieldDescriptor
+}
+
+// HasVolumeID returns a flag indicating whether the RPC's request has a
+// volume_id field.
+func (m *Method) HasVolumeID() bool {
+	return m.volumeID != nil
+}
+
+// VolumeID returns the value of the request's volume_id field. The flag
+// is false if the RPC's request has no volume_id field or req is not the
+// RPC's request.
+func (m *Method) VolumeID(req interface{}) (string, bool) {
+	return m.getString(req, m.volumeID)
+}
+
+// SourceVolumeID returns the value of the request's source_volume_id
+// field, ex. the ID of the volume from which CreateSnapshot creates a
+// snapshot. The flag is false if the RPC's request has no
+// source_volume_id field or req is not the RPC's request.
+func (m *Method) SourceVolumeID(req interface{}) (string, bool) {
+	return m.getString(req, m.sourceID)
+}
+
+// NodeID returns the value of the request's node_id field. The flag is
+// false if the RPC's request has no node_id field or req is not the RPC's
+// request.
+func (m *Method) NodeID(req interface{}) (string, bool) {
+	return m.getString(req, m.nodeID)
+}
+
+// DeletedID returns the ID of the object the RPC deletes, ex. the value
+// of the volume_id field for DeleteVolume. The flag is false if the RPC
+// does not delete an object or req is not the RPC's request.
+func (m *Method) DeletedID(req interface{}) (string, bool) {
+	return m.getString(req, m.deletedID)
+}
+
+// CreatedID returns the ID of the object the RPC creates, ex. the value
+// of the volume.volume_id field for CreateVolume. The flag is false if
+// the RPC does not create an object with an ID or rep is not the RPC's
+// response.
+func (m *Method) CreatedID(rep interface{}) (string, bool) {
+	if m.createdID == nil {
+		return "", false
+	}
+	v1, ok := rep.(protoadapt.MessageV1)
+	if !ok {
+		return "", false
+	}
+	msg := protoadapt.MessageV2Of(v1).ProtoReflect()
+	if msg.Descriptor() != m.response.Descriptor() || !msg.Has(m.created) {
+		return "", false
+	}
+	return msg.Get(m.created).Message().Get(m.createdID).String(), true
+}
+
+// HasName returns a flag indicating whether the RPC's request has a name
+// field, ex. the name of the volume created by CreateVolume.
+func (m *Method) HasName() bool {
+	return m.name != nil
+}
+
+// ObjectName returns the value of the request's name field. The flag is
+// false if the RPC's request has no name field or req is not the RPC's
+// request.
+func (m *Method) ObjectName(req interface{}) (string, bool) {
+	return m.getString(req, m.name)
+}
+
+// Secrets returns the value of the request's secrets field. A nil map is
+// returned if the RPC's request has no secrets or req is not the RPC's
+// request.
+func (m *Method) Secrets(req interface{}) map[string]string {
+	msg, ok := m.message(req, m.secrets)
+	if !ok || !msg.Has(m.secrets) {
+		return nil
+	}
+	secrets := map[string]string{}
+	msg.Get(m.secrets).Map().Range(
+		func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			secrets[k.String()] = v.String()
+			return true
+		})
+	return secrets
+}
+
+// WithSecrets returns a copy of the request whose secrets field is set to
+// the provided secrets, or cleared if secrets is empty. The request is
+// returned unmodified if the RPC's request has no secrets field or req is
+// not the RPC's request.
+func (m *Method) WithSecrets(
+	req interface{}, secrets map[string]string,
+) interface{} {
+	msg, ok := m.message(req, m.secrets)
+	if !ok {
+		return req
+	}
+	clone := proto.Clone(msg.Interface()).ProtoReflect()
+	clone.Clear(m.secrets)
+	if len(secrets) > 0 {
+		dst := clone.Mutable(m.secrets).Map()
+		for k, v := range secrets {
+			dst.Set(protoreflect.ValueOfString(k).MapKey(),
+				protoreflect.ValueOfString(v))
+		}
+	}
+	return protoadapt.MessageV1Of(clone.Interface())
+}
+
+// NewRequest returns a new, empty request for the RPC, ex.
+// *csi.NodePublishVolumeRequest.
+func (m *Method) NewRequest() interface{} {
+	return protoadapt.MessageV1Of(m.request.New().Interface())
+}
+
+// NewResponse returns a new, empty response for the RPC, ex.
+// *csi.NodePublishVolumeResponse.
+func (m *Method) NewResponse() interface{} {
+	return protoadapt.MessageV1Of(m.response.New().Interface())
+}
+
+func (m *Method) getString(
+	req interface{}, fd protoreflect.FieldDescriptor,
+) (string, bool) {
+	msg, ok := m.message(req, fd)
+	if !ok {
+		return "", false
+	}
+	return msg.Get(fd).String(), true
+}
+
+// message returns the reflective view of req if it is the RPC's request
+// and fd, a field of the request, is not nil.
+func (m *Method) message(
+	req interface{}, fd protoreflect.FieldDescriptor,
+) (protoreflect.Message, bool) {
+	if fd == nil {
+		return nil, false
+	}
+	v1, ok := req.(protoadapt.MessageV1)
+	if !ok {
+		return nil, false
+	}
+	msg := protoadapt.MessageV2Of(v1).ProtoReflect()
+	if msg.Descriptor() != m.request.Descriptor() {
+		return nil, false
+	}
+	return msg, true
+}
+
+var (
+	methods     []*Method
+	byMethod    = map[string]*Method{}
+	byRequest   = map[reflect.Type]*Method{}
+	byResponse  = map[reflect.Type]*Method{}
+	readOnlyRPC = []string{"Get", "List", "Validate", "Probe"}
+
+	// The RPCs below are classified by hand. Every mutating RPC must be
+	// classified by the "classified" registry test, which fails when the
+	// CSI specification adds an RPC that has not been reviewed.
+	cachedRPC = []string{
+		"CreateVolume",
+		"CreateSnapshot",
+		"ControllerPublishVolume",
+	}
+
+	invalidatesRPC = map[string][]string{
+		"DeleteVolume":              {"CreateVolume", "ControllerPublishVolume"},
+		"DeleteSnapshot":            {"CreateSnapshot"},
+		"ControllerUnpublishVolume": {"ControllerPublishVolume"},
+	}
+
+	serializedRPC = []string{
+		"CreateVolume",
+		"DeleteVolume",
+		"ControllerPublishVolume",
+		"ControllerUnpublishVolume",
+		"NodePublishVolume",
+		"NodeUnpublishVolume",
+	}
+)
+
+func init() {
+	fd := protoadapt.MessageV2Of(
+		&csi.GetPluginInfoRequest{}).ProtoReflect().Descriptor().ParentFile()
+	svcs := fd.Services()
+	for i := 0; i < svcs.Len(); i++ {
+		sd := svcs.Get(i)
+		mds := sd.Methods()
+		for j := 0; j < mds.Len(); j++ {
+			register(newMethod(sd, mds.Get(j)))
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].FullMethod < methods[j].FullMethod
+	})
+}
+
+func newMethod(
+	sd protoreflect.ServiceDescriptor, md protoreflect.MethodDescriptor,
+) *Method {
+	m := &Method{
+		FullMethod: fmt.Sprintf("/%s/%s", sd.FullName(), md.Name()),
+		Service:    string(sd.Name()),
+		Name:       string(md.Name()),
+		request:    messageType(md.Input()),
+		response:   messageType(md.Output()),
+	}
+
+	in := md.Input().Fields()
+	m.volumeID = stringField(in.ByName("volume_id"))
+	m.nodeID = stringField(in.ByName("node_id"))
+	m.name = stringField(in.ByName("name"))
+	m.sourceID = stringField(in.ByName("source_volume_id"))
+	for i := 0; i < in.Len(); i++ {
+		if f := in.Get(i); f.IsMap() && isSecret(f) {
+			m.secrets = f
+			m.SecretsField = string(f.Name())
+			break
+		}
+	}
+
+	m.Serialized = slices.Contains(serializedRPC, m.Name)
+	m.Cached = slices.Contains(cachedRPC, m.Name)
+	for _, name := range invalidatesRPC[m.Name] {
+		m.Invalidates = append(m.Invalidates,
+			fmt.Sprintf("/%s/%s", sd.FullName(), name))
+	}
+
+	if kind, ok := strings.CutPrefix(m.Name, "Delete"); ok {
+		kind = strings.ToLower(kind)
+		if m.deletedID = stringField(in.ByName(
+			protoreflect.Name(kind + "_id"))); m.deletedID != nil {
+			m.Deletes = kind
+		}
+	}
+
+	action := strings.TrimPrefix(strings.TrimPrefix(m.Name, "Controller"), "Node")
+	m.Mutating = true
+	for _, p := range readOnlyRPC {
+		if strings.HasPrefix(action, p) {
+			m.Mutating = false
+			break
+		}
+	}
+
+	switch {
+	case !m.Mutating:
+		m.Idempotency = IdempotencyReadOnly
+	case m.name != nil:
+		m.Idempotency = IdempotencyByName
+		out := md.Output().Fields()
+		for i := 0; i < out.Len(); i++ {
+			if f := out.Get(i); f.Kind() == protoreflect.MessageKind {
+				m.Creates = string(f.Name())
+				m.created = f
+				m.createdID = stringField(f.Message().Fields().ByName(
+					protoreflect.Name(m.Creates + "_id")))
+				break
+			}
+		}
+	default:
+		m.Idempotency = IdempotencyByID
+	}
+
+	return m
+}
+
+func register(m *Method) {
+	methods = append(methods, m)
+	byMethod[m.FullMethod] = m
+	byRequest[reflect.TypeOf(m.NewRequest())] = m
+	byResponse[reflect.TypeOf(m.NewResponse())] = m
+}
+
+// messageType returns the Go type of the provided message. The CSI
+// messages are registered when the csi package is initialized.
+func messageType(md protoreflect.MessageDescriptor) protoreflect.MessageType {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName())
+	if err != nil {
+		panic(fmt.Errorf("rpcs: unregistered message: %s: %v",
+			md.FullName(), err))
+	}
+	return mt
+}
+
+func stringField(f protoreflect.FieldDescriptor) protoreflect.FieldDescriptor {
+	if f == nil || f.Kind() != protoreflect.StringKind || f.IsList() {
+		return nil
+	}
+	return f
+}
+
+func isSecret(f protoreflect.FieldDescriptor) bool {
+	opts, ok := f.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil || !proto.HasExtension(opts, csi.E_CsiSecret) {
+		return false
+	}
+	v, _ := proto.GetExtension(opts, csi.E_CsiSecret).(bool)
+	return v
+}
+
+// Methods returns the descriptors of all the CSI RPCs, sorted by their
+// full methods.
+func Methods() []*Method {
+	return append([]*Method(nil), methods...)
+}
+
+// Lookup returns the descriptor of the CSI RPC with the provided gRPC
+// method, ex. "/csi.v1.Node/NodePublishVolume".
+func Lookup(fullMethod string) (*Method, bool) {
+	m, ok := byMethod[fullMethod]
+	return m, ok
+}
+
+// LookupRequest returns the descriptor of the CSI RPC whose request has
+// the type of req, ex. *csi.NodePublishVolumeRequest.
+func LookupRequest(req interface{}) (*Method, bool) {
+	m, ok := byRequest[reflect.TypeOf(req)]
+	return m, ok
+}
+
+// LookupResponse returns the descriptor of the CSI RPC whose response has
+// the type of rep, ex. *csi.NodePublishVolumeResponse.
+func LookupResponse(rep interface{}) (*Method, bool) {
+	m, ok := byResponse[reflect.TypeOf(rep)]
+	return m, ok
+}
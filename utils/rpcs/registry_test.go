@@ -0,0 +1,263 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package rpcs_test
+
+import (
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	"github.com/dell/gocsi/utils/rpcs"
+)
+
+var _ = ginkgo.Describe("Lookup", func() {
+	ginkgo.It("/csi.v1.Controller/CreateVolume", func() {
+		m, ok := rpcs.Lookup("/csi.v1.Controller/CreateVolume")
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(m.Service).Should(gomega.Equal("Controller"))
+		gomega.Ω(m.Name).Should(gomega.Equal("CreateVolume"))
+		gomega.Ω(m.Mutating).Should(gomega.BeTrue())
+		gomega.Ω(m.Idempotency).Should(gomega.Equal(rpcs.IdempotencyByName))
+		gomega.Ω(m.Creates).Should(gomega.Equal("volume"))
+		gomega.Ω(m.SecretsField).Should(gomega.Equal("secrets"))
+		gomega.Ω(m.HasVolumeID()).Should(gomega.BeFalse())
+		gomega.Ω(m.HasName()).Should(gomega.BeTrue())
+		gomega.Ω(m.NewRequest()).Should(gomega.BeAssignableToTypeOf(
+			&csi.CreateVolumeRequest{}))
+		gomega.Ω(m.NewResponse()).Should(gomega.BeAssignableToTypeOf(
+			&csi.CreateVolumeResponse{}))
+
+		req := &csi.CreateVolumeRequest{
+			Name:    "vol",
+			Secrets: map[string]string{"user": "admin"},
+		}
+		name, ok := m.ObjectName(req)
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(name).Should(gomega.Equal("vol"))
+		gomega.Ω(m.Secrets(req)).Should(gomega.Equal(
+			map[string]string{"user": "admin"}))
+		_, ok = m.VolumeID(req)
+		gomega.Ω(ok).Should(gomega.BeFalse())
+
+		cleared := m.WithSecrets(req, nil)
+		gomega.Ω(m.Secrets(cleared)).Should(gomega.BeNil())
+		gomega.Ω(m.Secrets(m.WithSecrets(req, map[string]string{
+			"user": "root"}))).Should(gomega.Equal(
+			map[string]string{"user": "root"}))
+		gomega.Ω(req.Secrets).Should(gomega.Equal(
+			map[string]string{"user": "admin"}))
+
+		id, ok := m.CreatedID(&csi.CreateVolumeResponse{
+			Volume: &csi.Volume{VolumeId: "1"},
+		})
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(id).Should(gomega.Equal("1"))
+		_, ok = m.CreatedID(&csi.CreateVolumeResponse{})
+		gomega.Ω(ok).Should(gomega.BeFalse())
+	})
+	ginkgo.It("/csi.v1.Controller/CreateSnapshot", func() {
+		m, ok := rpcs.Lookup("/csi.v1.Controller/CreateSnapshot")
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(m.Creates).Should(gomega.Equal("snapshot"))
+
+		srcID, ok := m.SourceVolumeID(&csi.CreateSnapshotRequest{
+			SourceVolumeId: "1",
+		})
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(srcID).Should(gomega.Equal("1"))
+		id, ok := m.CreatedID(&csi.CreateSnapshotResponse{
+			Snapshot: &csi.Snapshot{SnapshotId: "2"},
+		})
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(id).Should(gomega.Equal("2"))
+	})
+	ginkgo.It("/csi.v1.Controller/DeleteVolume", func() {
+		m, ok := rpcs.Lookup("/csi.v1.Controller/DeleteVolume")
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(m.Deletes).Should(gomega.Equal("volume"))
+		gomega.Ω(m.Invalidates).Should(gomega.Equal([]string{
+			"/csi.v1.Controller/CreateVolume",
+			"/csi.v1.Controller/ControllerPublishVolume",
+		}))
+
+		id, ok := m.DeletedID(&csi.DeleteVolumeRequest{VolumeId: "1"})
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(id).Should(gomega.Equal("1"))
+	})
+	ginkgo.It("/csi.v1.Controller/ControllerUnpublishVolume", func() {
+		m, ok := rpcs.Lookup("/csi.v1.Controller/ControllerUnpublishVolume")
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(m.Deletes).Should(gomega.BeEmpty())
+		gomega.Ω(m.Invalidates).Should(gomega.Equal([]string{
+			"/csi.v1.Controller/ControllerPublishVolume",
+		}))
+
+		nodeID, ok := m.NodeID(&csi.ControllerUnpublishVolumeRequest{
+			NodeId: "node-1",
+		})
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(nodeID).Should(gomega.Equal("node-1"))
+	})
+	ginkgo.It("/csi.v1.Node/NodeUnpublishVolume", func() {
+		m, ok := rpcs.Lookup("/csi.v1.Node/NodeUnpublishVolume")
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(m.Mutating).Should(gomega.BeTrue())
+		gomega.Ω(m.Idempotency).Should(gomega.Equal(rpcs.IdempotencyByID))
+		gomega.Ω(m.Creates).Should(gomega.BeEmpty())
+		gomega.Ω(m.SecretsField).Should(gomega.BeEmpty())
+
+		volID, ok := m.VolumeID(&csi.NodeUnpublishVolumeRequest{VolumeId: "1"})
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(volID).Should(gomega.Equal("1"))
+		gomega.Ω(m.Secrets(&csi.NodeUnpublishVolumeRequest{})).Should(gomega.BeNil())
+
+		// The accessors ignore the requests of other RPCs.
+		_, ok = m.VolumeID(&csi.NodePublishVolumeRequest{VolumeId: "1"})
+		gomega.Ω(ok).Should(gomega.BeFalse())
+	})
+	ginkgo.It("/csi.v1.Node/NodeGetVolumeStats", func() {
+		m, ok := rpcs.Lookup("/csi.v1.Node/NodeGetVolumeStats")
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(m.Mutating).Should(gomega.BeFalse())
+		gomega.Ω(m.Idempotency).Should(gomega.Equal(rpcs.IdempotencyReadOnly))
+		gomega.Ω(m.HasVolumeID()).Should(gomega.BeTrue())
+	})
+	ginkgo.It("/csi.v1.Identity/Probe", func() {
+		m, ok := rpcs.Lookup("/csi.v1.Identity/Probe")
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(m.Mutating).Should(gomega.BeFalse())
+		gomega.Ω(m.Idempotency.String()).Should(gomega.Equal("readOnly"))
+	})
+	ginkgo.It("/csi.v0.Identity/Probe", func() {
+		_, ok := rpcs.Lookup("/csi.v0.Identity/Probe")
+		gomega.Ω(ok).Should(gomega.BeFalse())
+	})
+})
+
+var _ = ginkgo.Describe("LookupRequest", func() {
+	ginkgo.It("*csi.DeleteSnapshotRequest", func() {
+		m, ok := rpcs.LookupRequest(&csi.DeleteSnapshotRequest{})
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(m.FullMethod).Should(gomega.Equal(
+			"/csi.v1.Controller/DeleteSnapshot"))
+	})
+	ginkgo.It("*csi.DeleteSnapshotResponse", func() {
+		_, ok := rpcs.LookupRequest(&csi.DeleteSnapshotResponse{})
+		gomega.Ω(ok).Should(gomega.BeFalse())
+		m, ok := rpcs.LookupResponse(&csi.DeleteSnapshotResponse{})
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(m.Name).Should(gomega.Equal("DeleteSnapshot"))
+	})
+})
+
+var _ = ginkgo.Describe("Methods", func() {
+	ginkgo.It("all", func() {
+		methods := rpcs.Methods()
+		gomega.Ω(methods).ShouldNot(gomega.BeEmpty())
+		for _, m := range methods {
+			byMethod, _ := rpcs.Lookup(m.FullMethod)
+			gomega.Ω(byMethod).Should(gomega.Equal(m))
+			byRequest, _ := rpcs.LookupRequest(m.NewRequest())
+			gomega.Ω(byRequest).Should(gomega.Equal(m))
+			byResponse, _ := rpcs.LookupResponse(m.NewResponse())
+			gomega.Ω(byResponse).Should(gomega.Equal(m))
+			gomega.Ω(rpcs.MethodNames(m.FullMethod)).Should(gomega.ContainElement(
+				m.Service + "/" + m.Name))
+		}
+	})
+	ginkgo.It("serialized", func() {
+		var serialized []string
+		for _, m := range rpcs.Methods() {
+			if m.Serialized {
+				serialized = append(serialized, m.Name)
+			}
+		}
+		gomega.Ω(serialized).Should(gomega.ConsistOf(
+			"CreateVolume",
+			"DeleteVolume",
+			"ControllerPublishVolume",
+			"ControllerUnpublishVolume",
+			"NodePublishVolume",
+			"NodeUnpublishVolume"))
+	})
+	ginkgo.It("cached", func() {
+		var cached []string
+		for _, m := range rpcs.Methods() {
+			if m.Cached {
+				cached = append(cached, m.Name)
+			}
+		}
+		gomega.Ω(cached).Should(gomega.ConsistOf(
+			"CreateVolume",
+			"CreateSnapshot",
+			"ControllerPublishVolume"))
+	})
+	ginkgo.It("classified", func() {
+		// The serialized, cached, and invalidating RPCs are listed by hand.
+		// Every mutating RPC must be reviewed and classified below, so an
+		// RPC added to the CSI specification fails this test until the
+		// lists in the registry are updated for it.
+		type class struct {
+			serialized, cached bool
+			invalidates        []string
+		}
+		classes := map[string]class{
+			"CreateVolume":              {serialized: true, cached: true},
+			"DeleteVolume":              {serialized: true, invalidates: []string{"CreateVolume", "ControllerPublishVolume"}},
+			"ControllerPublishVolume":   {serialized: true, cached: true},
+			"ControllerUnpublishVolume": {serialized: true, invalidates: []string{"ControllerPublishVolume"}},
+			"ControllerExpandVolume":    {},
+			"CreateSnapshot":            {cached: true},
+			"DeleteSnapshot":            {invalidates: []string{"CreateSnapshot"}},
+			"NodeStageVolume":           {},
+			"NodeUnstageVolume":         {},
+			"NodePublishVolume":         {serialized: true},
+			"NodeUnpublishVolume":       {serialized: true},
+			"NodeExpandVolume":          {},
+		}
+		var mutating []string
+		for _, m := range rpcs.Methods() {
+			if !m.Mutating {
+				continue
+			}
+			mutating = append(mutating, m.Name)
+			c, ok := classes[m.Name]
+			if !ok {
+				continue
+			}
+			gomega.Ω(m.Serialized).Should(gomega.Equal(c.serialized), m.Name)
+			gomega.Ω(m.Cached).Should(gomega.Equal(c.cached), m.Name)
+			var invalidates []string
+			for _, name := range c.invalidates {
+				invalidates = append(invalidates, "/csi.v1.Controller/"+name)
+			}
+			gomega.Ω(m.Invalidates).Should(gomega.Equal(invalidates), m.Name)
+		}
+		gomega.Ω(mutating).Should(gomega.ConsistOf(keys(classes)))
+	})
+})
+
+// keys returns the keys of the provided map.
+func keys[T any](m map[string]T) []string {
+	var k []string
+	for key := range m {
+		k = append(k, key)
+	}
+	return k
+}
@@ -21,7 +21,9 @@ package rpcs
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 )
 
 const parseMethodPatt = `^/csi\.v(\d+)\.([^/]+?)/(.+)$`
@@ -43,3 +45,53 @@ func ParseMethod(
 	}
 	return int32(v), m[2], m[3], nil
 }
+
+// AllMethods may be used in place of a method name in configuration to
+// refer to all methods.
+const AllMethods = "*"
+
+// MethodNames returns the names by which a gRPC method may be referenced
+// in configuration, from the most to the least specific: the full method,
+// ex. "/csi.v1.Node/NodePublishVolume", the service and method, ex.
+// "Node/NodePublishVolume", the method alone, ex. "NodePublishVolume",
+// all of the service's methods, ex. "Node/*", and AllMethods. Names are
+// case-sensitive.
+func MethodNames(fullMethod string) []string {
+	names := []string{fullMethod}
+	i := strings.LastIndex(fullMethod, "/")
+	if i < 0 {
+		return append(names, AllMethods)
+	}
+	svc := strings.TrimPrefix(fullMethod[:i], "/")
+	if j := strings.LastIndex(svc, "."); j >= 0 {
+		svc = svc[j+1:]
+	}
+	return append(names,
+		svc+"/"+fullMethod[i+1:],
+		fullMethod[i+1:],
+		svc+"/"+AllMethods,
+		AllMethods)
+}
+
+// LookupMethod returns the value in m of the most specific of the names
+// by which the provided method may be referenced. Please see MethodNames
+// for the names.
+func LookupMethod[T any](m map[string]T, fullMethod string) (T, bool) {
+	for _, n := range MethodNames(fullMethod) {
+		if v, ok := m[n]; ok {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// MatchMethod returns a flag indicating whether any of the provided
+// methods is one of the names by which the provided method may be
+// referenced. Please see MethodNames for the names.
+func MatchMethod(methods []string, fullMethod string) bool {
+	names := MethodNames(fullMethod)
+	return slices.ContainsFunc(methods, func(m string) bool {
+		return slices.Contains(names, m)
+	})
+}
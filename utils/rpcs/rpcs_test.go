@@ -83,3 +83,57 @@ var _ = ginkgo.Describe("ParseMethod", func() {
 				`parsing "%d": value out of range`, math.MaxInt64)))
 	})
 })
+
+var _ = ginkgo.Describe("MethodNames", func() {
+	ginkgo.It("/csi.v1.Node/NodeGetVolumeStats", func() {
+		gomega.Ω(rpcs.MethodNames("/csi.v1.Node/NodeGetVolumeStats")).
+			Should(gomega.Equal([]string{
+				"/csi.v1.Node/NodeGetVolumeStats",
+				"Node/NodeGetVolumeStats",
+				"NodeGetVolumeStats",
+				"Node/*",
+				"*",
+			}))
+	})
+	ginkgo.It("Probe", func() {
+		gomega.Ω(rpcs.MethodNames("Probe")).
+			Should(gomega.Equal([]string{"Probe", "*"}))
+	})
+})
+
+var _ = ginkgo.Describe("LookupMethod", func() {
+	m := map[string]int{
+		"Node/NodeGetVolumeStats": 1,
+		"Node/*":                  2,
+		rpcs.AllMethods:           3,
+	}
+	ginkgo.It("most specific", func() {
+		v, ok := rpcs.LookupMethod(m, "/csi.v1.Node/NodeGetVolumeStats")
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(v).Should(gomega.Equal(1))
+	})
+	ginkgo.It("service", func() {
+		v, _ := rpcs.LookupMethod(m, "/csi.v1.Node/NodeGetInfo")
+		gomega.Ω(v).Should(gomega.Equal(2))
+	})
+	ginkgo.It("all methods", func() {
+		v, _ := rpcs.LookupMethod(m, "/csi.v1.Identity/Probe")
+		gomega.Ω(v).Should(gomega.Equal(3))
+	})
+	ginkgo.It("case-sensitive", func() {
+		_, ok := rpcs.LookupMethod(map[string]int{"probe": 1},
+			"/csi.v1.Identity/Probe")
+		gomega.Ω(ok).Should(gomega.BeFalse())
+	})
+})
+
+var _ = ginkgo.Describe("MatchMethod", func() {
+	ginkgo.It("match", func() {
+		gomega.Ω(rpcs.MatchMethod([]string{"Probe", "Node/*"},
+			"/csi.v1.Node/NodeGetInfo")).Should(gomega.BeTrue())
+	})
+	ginkgo.It("no match", func() {
+		gomega.Ω(rpcs.MatchMethod([]string{"Probe", "Node/*"},
+			"/csi.v1.Controller/CreateVolume")).Should(gomega.BeFalse())
+	})
+})
@@ -0,0 +1,132 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package slogrus provides a log/slog handler that writes records to a
+// logrus logger. It is the default logging backend for GoCSI so that
+// plug-ins which configure the global logrus logger continue to work
+// unchanged.
+package slogrus
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sirupsen/logrus"
+)
+
+// New returns a new slog.Logger that writes to the provided logrus
+// logger. If l is nil then the standard logrus logger is used.
+func New(l *logrus.Logger) *slog.Logger {
+	return slog.New(NewHandler(l))
+}
+
+// NewHandler returns a new slog.Handler that writes to the provided
+// logrus logger. If l is nil then the standard logrus logger is used.
+//
+// Records are written at the logrus level that corresponds to the slog
+// level, and attributes are written as logrus fields. The keys of
+// attributes inside groups are prefixed with the group names separated
+// by dots.
+func NewHandler(l *logrus.Logger) slog.Handler {
+	if l == nil {
+		l = logrus.StandardLogger()
+	}
+	return &handler{l: l}
+}
+
+type handler struct {
+	l      *logrus.Logger
+	fields logrus.Fields
+	prefix string
+}
+
+// Level converts a slog.Level to the equivalent logrus.Level.
+func Level(lvl slog.Level) logrus.Level {
+	switch {
+	case lvl >= slog.LevelError:
+		return logrus.ErrorLevel
+	case lvl >= slog.LevelWarn:
+		return logrus.WarnLevel
+	case lvl >= slog.LevelInfo:
+		return logrus.InfoLevel
+	case lvl >= slog.LevelDebug:
+		return logrus.DebugLevel
+	}
+	return logrus.TraceLevel
+}
+
+func (h *handler) Enabled(_ context.Context, lvl slog.Level) bool {
+	return h.l.IsLevelEnabled(Level(lvl))
+}
+
+func (h *handler) Handle(_ context.Context, r slog.Record) error {
+	fields := make(logrus.Fields, len(h.fields)+r.NumAttrs())
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(fields, h.prefix, a)
+		return true
+	})
+	entry := h.l.WithFields(fields)
+	if !r.Time.IsZero() {
+		entry = entry.WithTime(r.Time)
+	}
+	entry.Log(Level(r.Level), r.Message)
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	fields := make(logrus.Fields, len(h.fields)+len(attrs))
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	for _, a := range attrs {
+		addAttr(fields, h.prefix, a)
+	}
+	return &handler{l: h.l, fields: fields, prefix: h.prefix}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &handler{l: h.l, fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+func addAttr(fields logrus.Fields, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		// Attributes of an inline group, one with an empty key, are
+		// added to the current group.
+		if a.Key != "" {
+			prefix = prefix + a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			addAttr(fields, prefix, ga)
+		}
+		return
+	}
+	fields[prefix+a.Key] = a.Value.Any()
+}
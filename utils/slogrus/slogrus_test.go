@@ -0,0 +1,64 @@
+/*
+ *
+ * Copyright © 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package slogrus
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevel(t *testing.T) {
+	assert.Equal(t, logrus.TraceLevel, Level(slog.LevelDebug-4))
+	assert.Equal(t, logrus.DebugLevel, Level(slog.LevelDebug))
+	assert.Equal(t, logrus.InfoLevel, Level(slog.LevelInfo))
+	assert.Equal(t, logrus.WarnLevel, Level(slog.LevelWarn))
+	assert.Equal(t, logrus.ErrorLevel, Level(slog.LevelError))
+	assert.Equal(t, logrus.ErrorLevel, Level(slog.LevelError+4))
+}
+
+func TestHandler(t *testing.T) {
+	l, hook := test.NewNullLogger()
+	l.SetLevel(logrus.InfoLevel)
+
+	lg := New(l).With("requestID", "0001").WithGroup("csi")
+
+	lg.Debug("hidden")
+	assert.Empty(t, hook.AllEntries())
+
+	lg.Warn("slow", "method", "Probe", slog.Group("vol", "id", "1"))
+	e := hook.LastEntry()
+	assert.Equal(t, logrus.WarnLevel, e.Level)
+	assert.Equal(t, "slow", e.Message)
+	assert.Equal(t, logrus.Fields{
+		"requestID":  "0001",
+		"csi.method": "Probe",
+		"csi.vol.id": "1",
+	}, e.Data)
+}
+
+func TestNewHandlerDefault(t *testing.T) {
+	h := NewHandler(nil).(*handler)
+	assert.Equal(t, logrus.StandardLogger(), h.l)
+	assert.Equal(t, h, h.WithAttrs(nil))
+	assert.Equal(t, h, h.WithGroup(""))
+}